@@ -0,0 +1,505 @@
+package qingfeng
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AggregateSource is an upstream service whose spec is merged into the served document
+// 聚合的上游服务文档
+type AggregateSource struct {
+	// Name 服务名（必填），用作默认标签命名空间，并用于重命名冲突的组件（如 billing.User）
+	Name string
+	// URL 文档地址：本地文件路径或 http(s) 地址
+	URL string
+	// PathPrefix 网关上的路径前缀，如 "/billing"；上游文档声明的 basePath/servers 路径会保留在前缀之后
+	PathPrefix string
+	// TagPrefix 标签命名空间，默认 Name；接口标签变为 "TagPrefix-原标签"，UI 中显示为该服务下的子分组
+	TagPrefix string
+	// Headers 拉取文档时附加的请求头（如鉴权）
+	Headers map[string]string
+}
+
+// AggregateConfig configures multi-service spec aggregation
+// 多服务文档聚合配置
+type AggregateConfig struct {
+	Sources []AggregateSource
+	// RefreshInterval 定时重新拉取的间隔，0 表示只在首次请求时拉取
+	RefreshInterval time.Duration
+	// Timeout 单个文档的拉取超时（默认 10s）
+	Timeout time.Duration
+	// Context 结束时停止定时刷新（如服务关闭时），nil 表示随进程一直运行
+	Context context.Context
+}
+
+// AggregateStatus is the fetch status of one source
+// 上游文档的拉取状态
+type AggregateStatus struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	OK   bool   `json:"ok"`
+	// Error 最近一次拉取失败的原因
+	Error string `json:"error,omitempty"`
+	// Stale 最近一次拉取失败，但仍在使用上一次成功拉取的文档
+	Stale bool `json:"stale,omitempty"`
+	// Operations 合并进文档的接口数
+	Operations int `json:"operations"`
+	// Conflicts 与其他来源冲突而被跳过的接口，如 "GET /billing/users"
+	Conflicts   []string   `json:"conflicts,omitempty"`
+	LastAttempt time.Time  `json:"lastAttempt"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+}
+
+// aggregator 拉取上游文档并与本地文档合并，合并结果会缓存到下一次刷新
+type aggregator struct {
+	cfg    Config
+	agg    AggregateConfig
+	base   []byte
+	client *http.Client
+
+	mu       sync.RWMutex
+	docs     map[string][]byte // 每个来源最近一次成功拉取的文档
+	statuses []AggregateStatus
+	merged   []byte
+
+	// onChange 合并结果变化时调用（不含首次合并）
+	onChange func(spec []byte)
+}
+
+func newAggregator(cfg Config, base []byte) *aggregator {
+	agg := *cfg.Aggregate
+	if agg.Timeout <= 0 {
+		agg.Timeout = 10 * time.Second
+	}
+	statuses := make([]AggregateStatus, len(agg.Sources))
+	for i, src := range agg.Sources {
+		statuses[i] = AggregateStatus{Name: src.Name, URL: src.URL}
+	}
+	return &aggregator{
+		cfg:      cfg,
+		agg:      agg,
+		base:     base,
+		client:   &http.Client{Timeout: agg.Timeout},
+		docs:     make(map[string][]byte),
+		statuses: statuses,
+	}
+}
+
+// start 按 RefreshInterval 定时刷新，直到 AggregateConfig.Context 结束
+func (a *aggregator) start() {
+	if a.agg.RefreshInterval <= 0 {
+		return
+	}
+	ctx := a.agg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	go func() {
+		ticker := time.NewTicker(a.agg.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// refresh 并发拉取所有来源并重新合并，拉取失败的来源沿用上一次成功的文档
+func (a *aggregator) refresh(ctx context.Context) {
+	type result struct {
+		data []byte
+		err  error
+	}
+	results := make([]result, len(a.agg.Sources))
+	var wg sync.WaitGroup
+	for i, src := range a.agg.Sources {
+		wg.Add(1)
+		go func(i int, src AggregateSource) {
+			defer wg.Done()
+			data, err := a.fetch(ctx, src)
+			results[i] = result{data, err}
+		}(i, src)
+	}
+	wg.Wait()
+
+	a.mu.Lock()
+	now := time.Now()
+	for i, src := range a.agg.Sources {
+		st := &a.statuses[i]
+		st.LastAttempt = now
+		if err := results[i].err; err != nil {
+			st.OK, st.Error = false, err.Error()
+			_, st.Stale = a.docs[src.Name]
+			log.Printf("[QingFeng] 拉取 %s 文档失败: %v\n", src.Name, err)
+			continue
+		}
+		a.docs[src.Name] = results[i].data
+		st.OK, st.Error, st.Stale = true, "", false
+		success := now
+		st.LastSuccess = &success
+	}
+
+	merged, stats, err := mergeAggregate(a.cfg, a.base, a.agg.Sources, a.docs)
+	if err != nil {
+		a.mu.Unlock()
+		log.Printf("[QingFeng] 合并聚合文档失败: %v\n", err)
+		return
+	}
+	for i, src := range a.agg.Sources {
+		a.statuses[i].Operations = stats[src.Name].operations
+		a.statuses[i].Conflicts = stats[src.Name].conflicts
+	}
+	changed := a.merged != nil && !bytes.Equal(a.merged, merged)
+	a.merged = merged
+	a.mu.Unlock()
+
+	if changed && a.onChange != nil {
+		a.onChange(merged)
+	}
+}
+
+// fetch 读取本地文件或通过 HTTP 拉取文档
+func (a *aggregator) fetch(ctx context.Context, src AggregateSource) ([]byte, error) {
+	var data []byte
+	if strings.HasPrefix(src.URL, "http://") || strings.HasPrefix(src.URL, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range src.Headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := a.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("获取文档失败: %s", resp.Status)
+		}
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, 32<<20)); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(src.URL); err != nil {
+			return nil, err
+		}
+	}
+	if parseSpec(data) == nil {
+		return nil, fmt.Errorf("文档不是有效的 JSON")
+	}
+	return data, nil
+}
+
+func (a *aggregator) spec() []byte {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.merged
+}
+
+func (a *aggregator) status() []AggregateStatus {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]AggregateStatus(nil), a.statuses...)
+}
+
+// aggregateStats 合并单个来源的统计
+type aggregateStats struct {
+	operations int
+	conflicts  []string
+}
+
+var componentNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// ensureMap 返回 m[key]，不存在时创建
+func ensureMap(m map[string]interface{}, key string) map[string]interface{} {
+	v, ok := m[key].(map[string]interface{})
+	if !ok {
+		v = make(map[string]interface{})
+		m[key] = v
+	}
+	return v
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rewriteRefs 将 #/definitions/ 引用统一为 #/components/schemas/，并按 renames 替换被重命名的组件引用
+func rewriteRefs(v interface{}, renames map[string]string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			if strings.HasPrefix(ref, "#/definitions/") {
+				ref = "#/components/schemas/" + strings.TrimPrefix(ref, "#/definitions/")
+			}
+			if renamed, ok := renames[ref]; ok {
+				ref = renamed
+			}
+			val["$ref"] = ref
+		}
+		for _, item := range val {
+			rewriteRefs(item, renames)
+		}
+	case []interface{}:
+		for _, item := range val {
+			rewriteRefs(item, renames)
+		}
+	}
+}
+
+// deepCopyValue 深拷贝 JSON 解析得到的值（map、数组与标量）
+func deepCopyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = deepCopyValue(item)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			list[i] = deepCopyValue(item)
+		}
+		return list
+	default:
+		return v
+	}
+}
+
+// renameSecurity 按 renames 替换安全要求中的方案名
+func renameSecurity(security interface{}, renames map[string]string) interface{} {
+	list, ok := security.([]interface{})
+	if !ok || len(renames) == 0 {
+		return security
+	}
+	result := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		req, ok := item.(map[string]interface{})
+		if !ok {
+			result = append(result, item)
+			continue
+		}
+		renamed := make(map[string]interface{}, len(req))
+		for name, scopes := range req {
+			if n, ok := renames[name]; ok {
+				name = n
+			}
+			renamed[name] = scopes
+		}
+		result = append(result, renamed)
+	}
+	return result
+}
+
+// mergeAggregate 将各来源文档合并进本地文档（为空时使用 cfg 生成的空文档），输出 OpenAPI 3
+// 同名且内容相同的组件共用一份，内容不同时重命名为 "服务名.组件名"；路径与方法都相同的接口保留先合并的一份
+func mergeAggregate(cfg Config, base []byte, sources []AggregateSource, docs map[string][]byte) ([]byte, map[string]aggregateStats, error) {
+	doc := normalizeSpec(base)
+	if doc == nil {
+		doc = newBaseSpec(cfg)
+	}
+	paths := ensureMap(doc, "paths")
+	components := ensureMap(doc, "components")
+	tags, _ := doc["tags"].([]interface{})
+	tagNames := make(map[string]bool)
+	for _, t := range tags {
+		if tag, ok := t.(map[string]interface{}); ok {
+			tagNames[getString(tag, "name")] = true
+		}
+	}
+	operationIDs := make(map[string]bool)
+	forEachOperation(doc, func(_, _ string, op map[string]interface{}) {
+		if id := getString(op, "operationId"); id != "" {
+			operationIDs[id] = true
+		}
+	})
+
+	stats := make(map[string]aggregateStats)
+	for _, source := range sources {
+		data, ok := docs[source.Name]
+		if !ok {
+			continue
+		}
+		src := normalizeSpec(data)
+		if src == nil {
+			continue
+		}
+		var st aggregateStats
+		namespace := componentNameUnsafe.ReplaceAllString(source.Name, "_")
+
+		// 组件：先确定重命名，再统一改写引用。组件按改写引用后的内容比较：
+		// 一个组件被重命名后，引用它的同名组件也会不同，因此重复比较直到不再产生新的重命名
+		renames := make(map[string]string)
+		securityRenames := make(map[string]string)
+		srcComponents, _ := src["components"].(map[string]interface{})
+		for changed := true; changed; {
+			changed = false
+			for _, section := range sortedKeys(srcComponents) {
+				entries, ok := srcComponents[section].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				target, _ := components[section].(map[string]interface{})
+				for _, name := range sortedKeys(entries) {
+					ref := "#/components/" + section + "/" + name
+					if _, renamed := renames[ref]; renamed {
+						continue
+					}
+					entry := deepCopyValue(entries[name])
+					rewriteRefs(entry, renames)
+					newName := name
+					for i := 1; ; i++ {
+						existing, exists := target[newName]
+						if !exists || reflect.DeepEqual(existing, entry) {
+							break
+						}
+						newName = namespace + "." + name
+						if i > 1 {
+							newName = fmt.Sprintf("%s.%s%d", namespace, name, i)
+						}
+					}
+					if newName == name {
+						continue
+					}
+					changed = true
+					renames[ref] = "#/components/" + section + "/" + newName
+					if section == "schemas" {
+						renames["#/definitions/"+name] = "#/components/schemas/" + newName
+					}
+					if section == "securitySchemes" {
+						securityRenames[name] = newName
+					}
+				}
+			}
+		}
+		rewriteRefs(src, renames)
+		for _, section := range sortedKeys(srcComponents) {
+			entries, ok := srcComponents[section].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			target := ensureMap(components, section)
+			for name, entry := range entries {
+				if renamed, ok := renames["#/components/"+section+"/"+name]; ok {
+					name = strings.TrimPrefix(renamed, "#/components/"+section+"/")
+				}
+				// 已存在的同名组件内容相同，保留原来的一份
+				if _, exists := target[name]; !exists {
+					target[name] = entry
+				}
+			}
+		}
+
+		// 标签命名空间
+		tagPrefix := source.TagPrefix
+		if tagPrefix == "" {
+			tagPrefix = source.Name
+		}
+		srcTags, _ := src["tags"].([]interface{})
+		for _, t := range srcTags {
+			tag, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tag = copyMap(tag)
+			tag["name"] = tagPrefix + "-" + getString(tag, "name")
+			if !tagNames[getString(tag, "name")] {
+				tagNames[getString(tag, "name")] = true
+				tags = append(tags, tag)
+			}
+		}
+
+		// 路径：网关前缀 + 上游 basePath + 原路径
+		prefix := "/" + strings.Trim(source.PathPrefix, "/")
+		if prefix == "/" {
+			prefix = ""
+		}
+		if bases := specBasePaths(src); len(bases) > 0 {
+			prefix += strings.TrimSuffix(bases[0], "/")
+		}
+		srcSecurity := renameSecurity(src["security"], securityRenames)
+		srcPaths, _ := src["paths"].(map[string]interface{})
+		for _, p := range sortedKeys(srcPaths) {
+			item, ok := srcPaths[p].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			newPath := prefix + p
+			if p == "/" && prefix != "" {
+				newPath = prefix
+			}
+			target := ensureMap(paths, newPath)
+			for _, key := range sortedKeys(item) {
+				op, isOp := item[key].(map[string]interface{})
+				if !isOp || !isHTTPMethod(key) {
+					if _, exists := target[key]; !exists {
+						target[key] = item[key]
+					}
+					continue
+				}
+				if _, exists := target[key]; exists {
+					st.conflicts = append(st.conflicts, strings.ToUpper(key)+" "+newPath)
+					continue
+				}
+				opTags := getStringArray(op, "tags")
+				namespaced := make([]interface{}, 0, len(opTags))
+				for _, t := range opTags {
+					namespaced = append(namespaced, tagPrefix+"-"+t)
+				}
+				if len(namespaced) == 0 {
+					namespaced = append(namespaced, tagPrefix)
+				}
+				op["tags"] = namespaced
+				if id := getString(op, "operationId"); id != "" {
+					if operationIDs[id] {
+						id = namespace + "_" + id
+						op["operationId"] = id
+					}
+					operationIDs[id] = true
+				}
+				if security, ok := op["security"]; ok {
+					op["security"] = renameSecurity(security, securityRenames)
+				} else if srcSecurity != nil {
+					op["security"] = srcSecurity
+				}
+				op["x-qingfeng-source"] = source.Name
+				target[key] = op
+				st.operations++
+			}
+		}
+		stats[source.Name] = st
+	}
+
+	if len(tags) > 0 {
+		doc["tags"] = tags
+	}
+	if len(components) == 0 {
+		delete(doc, "components")
+	}
+	data, err := json.Marshal(doc)
+	return data, stats, err
+}
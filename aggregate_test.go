@@ -0,0 +1,151 @@
+package qingfeng
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// aggregateBase 本地文档：Error 引用 Detail
+const aggregateBase = `{
+  "openapi": "3.0.3",
+  "info": {"title": "gateway", "version": "1.0.0"},
+  "paths": {
+    "/health": {"get": {"tags": ["System"], "responses": {"500": {"description": "error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}}}}
+  },
+  "components": {"schemas": {
+    "Error": {"type": "object", "properties": {"detail": {"$ref": "#/components/schemas/Detail"}}},
+    "Detail": {"type": "string"},
+    "Page": {"type": "object", "properties": {"total": {"type": "integer"}}}
+  }}
+}`
+
+// aggregateUpstream 上游文档：Error 与本地完全相同，但 Detail 不同；Page 与本地相同
+const aggregateUpstream = `{
+  "swagger": "2.0",
+  "info": {"title": "billing", "version": "1.0.0"},
+  "basePath": "/v1",
+  "tags": [{"name": "Invoice"}],
+  "paths": {
+    "/invoices": {"get": {"tags": ["Invoice"], "operationId": "listInvoices", "responses": {
+      "200": {"description": "ok", "schema": {"$ref": "#/definitions/Page"}},
+      "400": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+    }}},
+    "/health": {"get": {"responses": {"200": {"description": "ok"}}}}
+  },
+  "definitions": {
+    "Error": {"type": "object", "properties": {"detail": {"$ref": "#/definitions/Detail"}}},
+    "Detail": {"type": "object", "properties": {"code": {"type": "integer"}}},
+    "Page": {"type": "object", "properties": {"total": {"type": "integer"}}}
+  }
+}`
+
+// newUpstream 启动提供上游文档的本地服务，返回服务与拉取次数
+func newUpstream(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(aggregateUpstream))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func schemaRef(t *testing.T, doc map[string]interface{}, schema, prop string) string {
+	t.Helper()
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	s, ok := schemas[schema].(map[string]interface{})
+	if !ok {
+		t.Fatalf("缺少组件 %s", schema)
+	}
+	p := s["properties"].(map[string]interface{})[prop].(map[string]interface{})
+	return getString(p, "$ref")
+}
+
+func TestAggregateMerge(t *testing.T) {
+	srv, _ := newUpstream(t)
+	cfg := Config{Aggregate: &AggregateConfig{Sources: []AggregateSource{
+		{Name: "billing", URL: srv.URL, PathPrefix: "/billing"},
+	}}}
+	a := newAggregator(cfg, []byte(aggregateBase))
+	a.refresh(context.Background())
+	doc := parseSpec(a.spec())
+	if doc == nil {
+		t.Fatal("合并结果不是有效的 JSON")
+	}
+
+	// 本地的 Error 不能被改写为引用上游重命名后的 Detail
+	if ref := schemaRef(t, doc, "Error", "detail"); ref != "#/components/schemas/Detail" {
+		t.Errorf("本地 Error.detail 引用 = %s", ref)
+	}
+	// 上游的 Detail 与本地不同，重命名；引用它的 Error 因此也不同，同样重命名
+	if ref := schemaRef(t, doc, "billing.Error", "detail"); ref != "#/components/schemas/billing.Detail" {
+		t.Errorf("billing.Error.detail 引用 = %s", ref)
+	}
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if _, ok := schemas["billing.Detail"]; !ok {
+		t.Error("缺少重命名的 billing.Detail")
+	}
+	// 内容相同的 Page 共用一份
+	if _, ok := schemas["billing.Page"]; ok {
+		t.Error("相同的 Page 不应重命名")
+	}
+
+	paths := doc["paths"].(map[string]interface{})
+	item, ok := paths["/billing/v1/invoices"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("缺少带前缀的路径，paths = %v", sortedKeys(paths))
+	}
+	op := item["get"].(map[string]interface{})
+	if tags := getStringArray(op, "tags"); len(tags) != 1 || tags[0] != "billing-Invoice" {
+		t.Errorf("标签 = %v", tags)
+	}
+	responses := op["responses"].(map[string]interface{})
+	errSchema := responses["400"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if ref := getString(errSchema, "$ref"); ref != "#/components/schemas/billing.Error" {
+		t.Errorf("上游接口的错误响应引用 = %s", ref)
+	}
+	pageSchema := responses["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if ref := getString(pageSchema, "$ref"); ref != "#/components/schemas/Page" {
+		t.Errorf("上游接口的分页响应引用 = %s", ref)
+	}
+
+	// 本地接口保持不变
+	health := paths["/health"].(map[string]interface{})["get"].(map[string]interface{})
+	if tags := getStringArray(health, "tags"); len(tags) != 1 || tags[0] != "System" {
+		t.Errorf("本地接口标签 = %v", tags)
+	}
+	status := a.status()
+	if len(status) != 1 || !status[0].OK || status[0].Operations != 2 {
+		t.Errorf("状态 = %+v", status)
+	}
+}
+
+func TestAggregateStop(t *testing.T) {
+	srv, hits := newUpstream(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := Config{Aggregate: &AggregateConfig{
+		Sources:         []AggregateSource{{Name: "billing", URL: srv.URL}},
+		RefreshInterval: 10 * time.Millisecond,
+		Context:         ctx,
+	}}
+	a := newAggregator(cfg, nil)
+	a.start()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+	stopped := atomic.LoadInt32(hits)
+	if stopped == 0 {
+		t.Fatal("定时刷新没有执行")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(hits); n != stopped {
+		t.Errorf("Context 结束后仍在刷新：%d -> %d", stopped, n)
+	}
+}
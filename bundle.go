@@ -0,0 +1,405 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleSpec loads a multi-file spec (JSON or YAML) and resolves relative-file and URL $refs into one document
+// 加载拆分为多个文件的文档（JSON 或 YAML），解析相对路径与 URL 形式的外部 $ref 并打包为单个 JSON 文档：
+// schema、参数、响应等可复用的对象提升到 components（Swagger 2.0 为 definitions/parameters/responses），
+// 其余位置（如 paths 下引用的路径项）直接内联；循环引用通过组件引用自然闭合，无法提升的循环会返回错误。
+// 根文档为 JSON 且没有外部引用时原样返回，保持接口顺序不变。
+func BundleSpec(location string) ([]byte, error) {
+	b := &bundler{
+		docs:    make(map[string]interface{}),
+		hoists:  make(map[string]string),
+		hoisted: make(map[string]interface{}),
+		names:   make(map[string]string),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	root, err := b.absolute("", location)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := b.read(root)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeSpecDocument(raw)
+	if err != nil {
+		return nil, fmt.Errorf("解析文档 %s 失败: %w", location, err)
+	}
+	if json.Valid(raw) && !hasExternalRefs(doc) {
+		return raw, nil
+	}
+
+	spec, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("文档 %s 不是对象", location)
+	}
+	b.root = root
+	b.spec = spec
+	b.docs[root] = spec
+	b.swagger2 = spec["swagger"] != nil
+
+	bundled, err := b.walk(spec, root, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := bundled.(map[string]interface{})
+	for internal, component := range b.hoisted {
+		parts := strings.Split(strings.TrimPrefix(internal, "#/"), "/")
+		container := result
+		for _, part := range parts[:len(parts)-1] {
+			container = ensureMap(container, part)
+		}
+		container[parts[len(parts)-1]] = component
+	}
+	return json.Marshal(result)
+}
+
+// decodeSpecDocument 解析 JSON 或 YAML 文档，YAML 中的非字符串键（如响应码 200）转换为字符串
+func decodeSpecDocument(data []byte) (interface{}, error) {
+	var doc interface{}
+	if json.Valid(data) {
+		err := json.Unmarshal(data, &doc)
+		return doc, err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return normalizeYAML(doc), nil
+}
+
+func normalizeYAML(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = normalizeYAML(item)
+		}
+		return val
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalizeYAML(item)
+		}
+		return val
+	}
+	return v
+}
+
+// hasExternalRefs 判断文档中是否存在指向其他文件或 URL 的 $ref
+func hasExternalRefs(v interface{}) bool {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok && !strings.HasPrefix(ref, "#") {
+			return true
+		}
+		for _, item := range val {
+			if hasExternalRefs(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range val {
+			if hasExternalRefs(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bundler 打包过程中的状态
+type bundler struct {
+	root     string
+	spec     map[string]interface{}
+	swagger2 bool
+	client   *http.Client
+
+	docs    map[string]interface{} // 已加载的文档，键为绝对路径或 URL
+	hoists  map[string]string      // 外部引用（位置#片段）→ 根文档中的内部引用
+	hoisted map[string]interface{} // 内部引用 → 提升的组件内容，打包完成后写入根文档
+	names   map[string]string      // 已占用的组件引用 → 外部引用，用于处理重名
+	stack   []string               // 正在内联的外部引用，用于检测循环
+}
+
+// absolute 将 ref 中的文件部分相对 base（文件路径或 URL）解析为绝对位置
+func (b *bundler) absolute(base, ref string) (string, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return ref, nil
+	}
+	if strings.HasPrefix(base, "http://") || strings.HasPrefix(base, "https://") {
+		u, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		r, err := url.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		return u.ResolveReference(r).String(), nil
+	}
+	if base != "" && !filepath.IsAbs(ref) {
+		ref = filepath.Join(filepath.Dir(base), ref)
+	}
+	return filepath.Abs(ref)
+}
+
+func (b *bundler) read(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.ReadFile(location)
+	}
+	resp, err := b.client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("获取 %s 失败: %s", location, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+}
+
+func (b *bundler) load(location string) (interface{}, error) {
+	if doc, ok := b.docs[location]; ok {
+		return doc, nil
+	}
+	data, err := b.read(location)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeSpecDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", location, err)
+	}
+	b.docs[location] = doc
+	return doc, nil
+}
+
+// pointerGet 按 JSON Pointer 片段（如 /components/schemas/User）取值
+func pointerGet(doc interface{}, fragment string) (interface{}, bool) {
+	cur := doc
+	if fragment == "" || fragment == "/" {
+		return cur, true
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		switch val := cur.(type) {
+		case map[string]interface{}:
+			next, ok := val[part]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(val) {
+				return nil, false
+			}
+			cur = val[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// walk 复制 v 并处理其中的 $ref；location 为 v 所在文档的位置，trail 为 v 在根文档中的路径
+func (b *bundler) walk(v interface{}, location string, trail []string) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			return b.resolve(ref, location, trail)
+		}
+		// 按键排序遍历，重名组件的序号（User、User2）在每次打包时保持一致
+		result := make(map[string]interface{}, len(val))
+		for _, k := range sortedKeys(val) {
+			resolved, err := b.walk(val[k], location, append(trail, k))
+			if err != nil {
+				return nil, err
+			}
+			result[k] = resolved
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			resolved, err := b.walk(item, location, append(trail, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+		return result, nil
+	}
+	return v, nil
+}
+
+// resolve 处理一个 $ref：根文档内部引用保持不变，外部引用提升为组件或内联
+func (b *bundler) resolve(ref, location string, trail []string) (interface{}, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	target := location
+	if file != "" {
+		var err error
+		if target, err = b.absolute(location, file); err != nil {
+			return nil, err
+		}
+	}
+	if target == b.root {
+		return map[string]interface{}{"$ref": "#" + fragment}, nil
+	}
+	key := target + "#" + fragment
+	if internal, ok := b.hoists[key]; ok {
+		return map[string]interface{}{"$ref": internal}, nil
+	}
+
+	doc, err := b.load(target)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := pointerGet(doc, fragment)
+	if !ok {
+		return nil, fmt.Errorf("无法解析引用 %s", ref)
+	}
+
+	section := b.hoistSection(trail, fragment)
+	if section == "" {
+		for _, k := range b.stack {
+			if k == key {
+				return nil, fmt.Errorf("检测到无法打包的循环引用: %s", key)
+			}
+		}
+		b.stack = append(b.stack, key)
+		defer func() { b.stack = b.stack[:len(b.stack)-1] }()
+		return b.walk(node, target, trail)
+	}
+
+	// 先登记引用再处理内容，递归引用会指向同一个组件
+	internal := b.componentRef(section, b.componentName(target, fragment), key)
+	b.hoists[key] = internal
+	resolved, err := b.walk(node, target, strings.Split(strings.TrimPrefix(internal, "#/"), "/"))
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := resolved.(map[string]interface{}); ok && section == "schemas" && strings.Trim(fragment, "/") == "" {
+		// 整个文件作为 schema 时，其中被引用到的 definitions/$defs 已单独提升
+		delete(m, "definitions")
+		delete(m, "$defs")
+	}
+	b.hoisted[internal] = resolved
+	return map[string]interface{}{"$ref": internal}, nil
+}
+
+// hoistSection 根据引用出现的位置与目标片段判断应提升到的组件分类，返回空字符串表示内联
+// Swagger 2.0 只有 definitions、parameters、responses 三类可复用对象
+func (b *bundler) hoistSection(trail []string, fragment string) string {
+	section := b.referenceSection(trail, fragment)
+	if b.swagger2 && section != "schemas" && section != "parameters" && section != "responses" {
+		return ""
+	}
+	return section
+}
+
+func (b *bundler) referenceSection(trail []string, fragment string) string {
+	// 目标本身位于组件中时沿用其分类
+	parts := strings.Split(strings.Trim(fragment, "/"), "/")
+	if len(parts) == 3 && parts[0] == "components" {
+		return parts[1]
+	}
+	if len(parts) == 2 {
+		switch parts[0] {
+		case "definitions":
+			return "schemas"
+		case "parameters", "responses":
+			return parts[0]
+		}
+	}
+
+	n := len(trail)
+	if n == 0 {
+		return ""
+	}
+	last := trail[n-1]
+	parent := ""
+	if n > 1 {
+		parent = trail[n-2]
+	}
+	switch {
+	case n >= 2 && (trail[0] == "definitions" || (trail[0] == "components" && trail[1] == "schemas")):
+		return "schemas"
+	case last == "schema" || last == "items" || last == "not" || last == "additionalProperties":
+		return "schemas"
+	case parent == "properties" || parent == "allOf" || parent == "anyOf" || parent == "oneOf" || parent == "patternProperties":
+		return "schemas"
+	case parent == "parameters":
+		return "parameters"
+	case parent == "responses" && n >= 3:
+		return "responses"
+	case last == "requestBody":
+		return "requestBodies"
+	case parent == "headers":
+		return "headers"
+	case parent == "examples":
+		return "examples"
+	}
+	return ""
+}
+
+// componentName 组件名：片段的最后一段，整个文件被引用时使用文件名
+func (b *bundler) componentName(location, fragment string) string {
+	name := fragment[strings.LastIndex(fragment, "/")+1:]
+	if name == "" {
+		base := path.Base(filepath.ToSlash(location))
+		name = strings.TrimSuffix(base, path.Ext(base))
+	}
+	return componentNameUnsafe.ReplaceAllString(name, "_")
+}
+
+// componentRef 返回组件的内部引用，名称已被其他外部引用占用时追加序号
+func (b *bundler) componentRef(section, name, key string) string {
+	prefix := "#/components/" + section + "/"
+	if b.swagger2 {
+		switch section {
+		case "schemas":
+			prefix = "#/definitions/"
+		case "parameters", "responses":
+			prefix = "#/" + section + "/"
+		}
+	}
+	existing, _ := pointerGet(b.spec, strings.TrimSuffix(strings.TrimPrefix(prefix, "#"), "/"))
+	for i := 1; ; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s%d", name, i)
+		}
+		ref := prefix + candidate
+		owner, taken := b.names[ref]
+		if m, ok := existing.(map[string]interface{}); ok && !taken {
+			if _, exists := m[candidate]; exists {
+				continue
+			}
+		}
+		if !taken || owner == key {
+			b.names[ref] = key
+			return ref
+		}
+	}
+}
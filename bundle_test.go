@@ -0,0 +1,82 @@
+package qingfeng
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeBundleFiles 写出根文档与两个定义了同名 User 的外部文件
+func writeBundleFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"openapi.yaml": `openapi: 3.0.3
+info: {title: bundle, version: 1.0.0}
+paths:
+  /accounts:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "accounts.yaml#/User"}
+  /members:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "members.yaml#/User"}
+`,
+		"accounts.yaml": "User: {type: object, properties: {account: {type: string}}}\n",
+		"members.yaml":  "User: {type: object, properties: {member: {type: string}}}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "openapi.yaml")
+}
+
+func TestBundleSpecCollidingNames(t *testing.T) {
+	root := writeBundleFiles(t)
+	first, err := BundleSpec(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		out, err := BundleSpec(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, first) {
+			t.Fatalf("第 %d 次打包结果不同:\n%s\n%s", i+2, first, out)
+		}
+	}
+
+	doc := parseSpec(first)
+	paths := doc["paths"].(map[string]interface{})
+	refOf := func(path string) string {
+		schema := paths[path].(map[string]interface{})["get"].(map[string]interface{})["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+		return getString(schema, "$ref")
+	}
+	if ref := refOf("/accounts"); ref != "#/components/schemas/User" {
+		t.Errorf("/accounts 引用 = %s", ref)
+	}
+	if ref := refOf("/members"); ref != "#/components/schemas/User2" {
+		t.Errorf("/members 引用 = %s", ref)
+	}
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for name, prop := range map[string]string{"User": "account", "User2": "member"} {
+		schema, _ := schemas[name].(map[string]interface{})
+		props, _ := schema["properties"].(map[string]interface{})
+		if _, ok := props[prop]; !ok {
+			t.Errorf("%s 应包含属性 %s: %v", name, prop, schema)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package qingfeng
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// minCompressSize 小于该大小的响应不压缩
+const minCompressSize = 1024
+
+// immutableCacheControl 带内容哈希的 URL 内容永不变化，浏览器可长期缓存且无需重新验证
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+// contentEncodings 支持的压缩编码，按优先级排列
+var contentEncodings = []string{"br", "gzip"}
+
+// cachedBody 一份带 ETag 的响应内容，按需生成并缓存各压缩编码的版本
+type cachedBody struct {
+	contentType  string
+	etag         string
+	body         []byte
+	compressible bool
+
+	mu      sync.Mutex
+	encoded map[string][]byte
+}
+
+func newCachedBody(contentType string, body []byte) *cachedBody {
+	sum := sha256.Sum256(body)
+	return &cachedBody{
+		contentType:  contentType,
+		etag:         `"` + hex.EncodeToString(sum[:8]) + `"`,
+		body:         body,
+		compressible: len(body) >= minCompressSize && isCompressibleType(contentType),
+		encoded:      make(map[string][]byte),
+	}
+}
+
+// encoding 返回指定编码压缩后的内容，首次调用时压缩并缓存
+func (c *cachedBody) encoding(enc string) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if data, ok := c.encoded[enc]; ok {
+		return data
+	}
+	data, err := compressBody(enc, c.body, false)
+	if err != nil {
+		log.Printf("[QingFeng] 压缩响应失败: %v\n", err)
+	}
+	c.encoded[enc] = data
+	return data
+}
+
+// variantETag 压缩版本使用独立的 ETag，避免中间缓存混用不同编码的内容
+func (c *cachedBody) variantETag(enc string) string {
+	return strings.TrimSuffix(c.etag, `"`) + "-" + enc + `"`
+}
+
+// write 写出响应：If-None-Match 命中时返回 304，否则按 Accept-Encoding 协商压缩
+func (c *cachedBody) write(w http.ResponseWriter, r *http.Request, cacheControl string) {
+	h := w.Header()
+	h.Set("Cache-Control", cacheControl)
+	enc := ""
+	if c.compressible {
+		h.Add("Vary", "Accept-Encoding")
+		enc = negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	}
+	body := c.body
+	etag := c.etag
+	if enc != "" {
+		if data := c.encoding(enc); data != nil {
+			body = data
+			etag = c.variantETag(enc)
+			h.Set("Content-Encoding", enc)
+		}
+	}
+	h.Set("ETag", etag)
+
+	inm := r.Header.Get("If-None-Match")
+	if inm != "" && (etagMatches(inm, etag) || etagMatches(inm, c.etag)) {
+		h.Del("Content-Encoding")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", c.contentType)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+// dynamicBodies 缓存动态响应（文档、配置等）的压缩结果，按 ETag 复用
+var dynamicBodies = struct {
+	sync.Mutex
+	items map[string]*cachedBody
+}{items: make(map[string]*cachedBody)}
+
+// maxDynamicBodies 动态响应缓存的最大条目数，超出时整体清空
+const maxDynamicBodies = 64
+
+// writeCacheable 写出带 ETag 的响应，浏览器每次使用前重新验证，内容未变化时返回 304；
+// 支持 gzip / brotli 压缩，同一内容只压缩一次
+func writeCacheable(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	c := newCachedBody(contentType, body)
+	dynamicBodies.Lock()
+	key := contentType + " " + c.etag
+	if cached, ok := dynamicBodies.items[key]; ok && bytes.Equal(cached.body, body) {
+		c = cached
+	} else {
+		if len(dynamicBodies.items) >= maxDynamicBodies {
+			dynamicBodies.items = make(map[string]*cachedBody)
+		}
+		dynamicBodies.items[key] = c
+	}
+	dynamicBodies.Unlock()
+	c.write(w, r, "no-cache")
+}
+
+// etagMatches 判断 If-None-Match 是否包含指定 ETag（支持多个值、弱校验与 *）
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding 根据 Accept-Encoding 选择压缩编码，q 值相同时优先 brotli，不支持压缩时返回空字符串
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		qualities[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range contentEncodings {
+		q, ok := qualities[enc]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// compressBody 按编码压缩内容，best 为 true 时使用最高压缩率（构建时预压缩）
+func compressBody(enc string, body []byte, best bool) ([]byte, error) {
+	var buf bytes.Buffer
+	var zw io.WriteCloser
+	switch enc {
+	case "gzip":
+		level := gzip.DefaultCompression
+		if best {
+			level = gzip.BestCompression
+		}
+		gw, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		zw = gw
+	case "br":
+		level := 5
+		if best {
+			level = brotli.BestCompression
+		}
+		zw = brotli.NewWriterLevel(&buf, level)
+	default:
+		return nil, nil
+	}
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressBody 解压预压缩文件，用于校验其是否与源文件一致
+func decompressBody(enc string, data []byte) ([]byte, error) {
+	var zr io.Reader
+	switch enc {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		zr = gr
+	case "br":
+		zr = brotli.NewReader(bytes.NewReader(data))
+	default:
+		return nil, nil
+	}
+	return io.ReadAll(zr)
+}
+
+// precompressedExt 预压缩文件的扩展名
+var precompressedExt = map[string]string{"gzip": ".gz", "br": ".br"}
+
+// isCompressibleType 判断内容类型是否值得压缩（字体、图片等已压缩的格式除外）
+func isCompressibleType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case strings.HasSuffix(mediaType, "json"), strings.HasSuffix(mediaType, "javascript"),
+		strings.HasSuffix(mediaType, "xml"), mediaType == "image/svg+xml", mediaType == "application/yaml":
+		return true
+	}
+	return false
+}
+
+// assetContentType 按扩展名返回内容类型
+func assetContentType(name string) string {
+	ext := path.Ext(name)
+	switch ext {
+	case ".woff2":
+		return "font/woff2"
+	case ".woff":
+		return "font/woff"
+	case ".js":
+		return "text/javascript; charset=utf-8"
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// loadStaticFiles 读取 fsys 中的所有静态文件；构建时生成的 .gz / .br 预压缩文件
+// 与源文件一致时直接使用，否则在首次请求时压缩
+func loadStaticFiles(fsys fs.FS) map[string]*cachedBody {
+	files := make(map[string]*cachedBody)
+	fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || isPrecompressedFile(name) {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil
+		}
+		c := newCachedBody(assetContentType(name), data)
+		if c.compressible {
+			for enc, ext := range precompressedExt {
+				packed, err := fs.ReadFile(fsys, name+ext)
+				if err != nil {
+					continue
+				}
+				if plain, err := decompressBody(enc, packed); err == nil && bytes.Equal(plain, data) {
+					c.encoded[enc] = packed
+				} else {
+					log.Printf("[QingFeng] 预压缩文件 %s 已过期，请执行 go generate\n", name+ext)
+				}
+			}
+		}
+		files[name] = c
+		return nil
+	})
+	return files
+}
+
+// isPrecompressedFile 判断是否为预压缩生成的文件
+func isPrecompressedFile(name string) bool {
+	for _, ext := range precompressedExt {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// staticVersion 计算一组静态文件的内容哈希，作为 URL 中的版本号
+func staticVersion(files map[string]*cachedBody) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		io.WriteString(h, name+"\x00"+files[name].etag+"\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil))[:10]
+}
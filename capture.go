@@ -0,0 +1,575 @@
+package qingfeng
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRedact 默认脱敏规则：字段名（不区分大小写，忽略 - 与 _）包含其中任意一项即脱敏
+var defaultRedact = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie", "apiKey", "creditCard", "cardNumber",
+}
+
+// CaptureConfig configures traffic capture
+// 流量采集配置（建议仅在开发环境启用）
+type CaptureConfig struct {
+	// File is the ring buffer file (default "./docs/captures.json")
+	// 采集结果保存文件，重启后继续使用
+	File string
+	// MaxPerOperation is the number of exchanges kept per operation (default 5)
+	// 每个接口保留的最近请求数
+	MaxPerOperation int
+	// SampleRate is the sampling probability 0-1 (default 1)
+	// 采样率
+	SampleRate float64
+	// Redact lists extra field/header/query names to mask (substring match), in addition to passwords, tokens, cookies, etc.
+	// 额外需要脱敏的字段名（JSON 字段、查询参数与请求头，包含即匹配），默认已包含密码、令牌、Cookie 等
+	Redact []string
+	// MaxBodySize limits captured body size in bytes (default 64KB)
+	// 采集的请求体/响应体大小上限，超过时不记录该请求体
+	MaxBodySize int64
+	// Prefixes are extra path prefixes stripped before matching (匹配前额外去掉的路径前缀)
+	Prefixes []string
+}
+
+// CapturedExchange is a recorded request/response pair
+// 采集到的一次请求与响应
+type CapturedExchange struct {
+	Time         time.Time           `json:"time"`
+	Method       string              `json:"method"`
+	URL          string              `json:"url"`
+	Query        map[string][]string `json:"query,omitempty"`
+	Headers      map[string]string   `json:"headers,omitempty"`
+	RequestType  string              `json:"requestType,omitempty"`
+	RequestBody  interface{}         `json:"requestBody,omitempty"`
+	Status       int                 `json:"status"`
+	ResponseType string              `json:"responseType,omitempty"`
+	ResponseBody interface{}         `json:"responseBody,omitempty"`
+}
+
+// Capture records sampled traffic per documented operation and merges it into the spec as examples
+// 按文档中的接口采集真实请求/响应，脱敏后保存到本地环形缓冲文件，并作为 examples 合并进文档
+type Capture struct {
+	cfg    CaptureConfig
+	redact []string
+
+	mu        sync.Mutex
+	spec      map[string]interface{}
+	routes    *routeIndex
+	exchanges map[string][]CapturedExchange // "GET /users/{id}" -> 最近的请求
+	rand      *rand.Rand
+	saving    bool
+}
+
+// NewCapture creates a capture store; spec may be nil when used with Config.Capture
+// 创建流量采集器并加载已保存的采集结果，spec 为 nil 时使用 Config.Capture 所在文档
+func NewCapture(spec []byte, cfg CaptureConfig) *Capture {
+	if cfg.File == "" {
+		cfg.File = "./docs/captures.json"
+	}
+	if cfg.MaxPerOperation <= 0 {
+		cfg.MaxPerOperation = 5
+	}
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 1
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = 64 << 10
+	}
+	c := &Capture{
+		cfg:       cfg,
+		exchanges: make(map[string][]CapturedExchange),
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, name := range append(defaultRedact, cfg.Redact...) {
+		c.redact = append(c.redact, redactKey(name))
+	}
+	if spec != nil {
+		c.spec = normalizeSpec(spec)
+		c.routes = newRouteIndex(c.spec)
+	}
+	if data, err := os.ReadFile(cfg.File); err == nil {
+		json.Unmarshal(data, &c.exchanges)
+	}
+	return c
+}
+
+// setSpec 在未指定文档时设置匹配用的文档
+func (c *Capture) setSpec(spec []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.spec == nil {
+		c.spec = normalizeSpec(spec)
+		c.routes = newRouteIndex(c.spec)
+	}
+}
+
+// Middleware returns a net/http middleware that records traffic
+// 返回采集流量的 net/http 中间件
+func (c *Capture) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		match, body := c.begin(r)
+		if match == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tw := &teeWriter{ResponseWriter: w, limit: c.cfg.MaxBodySize}
+		next.ServeHTTP(tw, r)
+		c.record(r, match, body, tw.status, tw.Header().Get("Content-Type"), tw.body.Bytes(), tw.overflow)
+	})
+}
+
+// Gin returns the capture middleware for Gin
+// 返回 Gin 框架的采集中间件
+func (c *Capture) Gin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		match, body := c.begin(ctx.Request)
+		if match == nil {
+			ctx.Next()
+			return
+		}
+		tw := &ginTeeWriter{ResponseWriter: ctx.Writer, limit: c.cfg.MaxBodySize}
+		ctx.Writer = tw
+		ctx.Next()
+		ctx.Writer = tw.ResponseWriter
+		c.record(ctx.Request, match, body, tw.Status(), tw.Header().Get("Content-Type"), tw.body.Bytes(), tw.overflow)
+	}
+}
+
+// begin 匹配接口并按采样率决定是否采集，采集时读取并还原请求体
+func (c *Capture) begin(r *http.Request) (*operationMatch, []byte) {
+	c.mu.Lock()
+	spec, routes := c.spec, c.routes
+	sampled := c.rand.Float64() < c.cfg.SampleRate
+	c.mu.Unlock()
+	if spec == nil || !sampled {
+		return nil, nil
+	}
+	match := routes.matchOperation(r.Method, pathCandidates(spec, r.URL.Path, c.cfg.Prefixes))
+	if match == nil {
+		return nil, nil
+	}
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		body, _ = io.ReadAll(io.LimitReader(r.Body, c.cfg.MaxBodySize+1))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		if int64(len(body)) > c.cfg.MaxBodySize {
+			body = nil
+		}
+	}
+	return match, body
+}
+
+// record 脱敏并保存一次请求
+func (c *Capture) record(r *http.Request, match *operationMatch, reqBody []byte, status int, respType string, respBody []byte, overflow bool) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ex := CapturedExchange{
+		Time:         time.Now().Truncate(time.Second),
+		Method:       r.Method,
+		URL:          r.URL.Path,
+		Status:       status,
+		RequestType:  r.Header.Get("Content-Type"),
+		ResponseType: respType,
+	}
+	if query := r.URL.Query(); len(query) > 0 {
+		ex.Query = make(map[string][]string, len(query))
+		for k, v := range query {
+			if c.sensitive(k) {
+				v = []string{"***"}
+			}
+			ex.Query[k] = v
+		}
+	}
+	c.mu.Lock()
+	spec := c.spec
+	c.mu.Unlock()
+	// 只记录文档中声明的请求头参数
+	for _, param := range operationParameters(spec, match.Path, match.op) {
+		name := getString(param, "name")
+		if getString(param, "in") != "header" || r.Header.Get(name) == "" {
+			continue
+		}
+		if ex.Headers == nil {
+			ex.Headers = make(map[string]string)
+		}
+		value := r.Header.Get(name)
+		if c.sensitive(name) {
+			value = "***"
+		}
+		ex.Headers[name] = value
+	}
+	ex.RequestBody = c.parseBody(ex.RequestType, reqBody)
+	if !overflow {
+		ex.ResponseBody = c.parseBody(respType, respBody)
+	}
+
+	key := strings.ToUpper(match.Method) + " " + match.Path
+	c.mu.Lock()
+	list := append(c.exchanges[key], ex)
+	if len(list) > c.cfg.MaxPerOperation {
+		list = list[len(list)-c.cfg.MaxPerOperation:]
+	}
+	c.exchanges[key] = list
+	if !c.saving {
+		c.saving = true
+		time.AfterFunc(time.Second, func() { c.Save() })
+	}
+	c.mu.Unlock()
+}
+
+// parseBody 解析并脱敏 JSON 请求体/响应体，非 JSON 内容不记录
+func (c *Capture) parseBody(contentType string, body []byte) interface{} {
+	if len(bytes.TrimSpace(body)) == 0 || !isJSONMediaType(contentType) {
+		return nil
+	}
+	var v interface{}
+	if json.Unmarshal(body, &v) != nil {
+		return nil
+	}
+	return c.redactValue(v)
+}
+
+func (c *Capture) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if c.sensitive(k) {
+				val[k] = "***"
+				continue
+			}
+			val[k] = c.redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = c.redactValue(item)
+		}
+	}
+	return v
+}
+
+// sensitive 判断字段名是否需要脱敏
+func (c *Capture) sensitive(name string) bool {
+	key := redactKey(name)
+	for _, r := range c.redact {
+		if strings.Contains(key, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactKey 归一化字段名：小写并去掉 - 与 _
+func redactKey(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+// Exchanges returns a copy of the recorded exchanges keyed by "METHOD /path"
+// 返回采集结果，key 为 "GET /users/{id}"
+func (c *Capture) Exchanges() map[string][]CapturedExchange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make(map[string][]CapturedExchange, len(c.exchanges))
+	for k, v := range c.exchanges {
+		result[k] = append([]CapturedExchange(nil), v...)
+	}
+	return result
+}
+
+// Save writes the ring buffer to CaptureConfig.File (保存采集结果到文件)
+func (c *Capture) Save() error {
+	c.mu.Lock()
+	c.saving = false
+	data, err := json.MarshalIndent(c.exchanges, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.cfg.File, data)
+}
+
+// Merge merges recorded exchanges into spec as observed examples (OpenAPI 3 output)
+// 将采集结果作为 examples（带 x-observed 标记）合并进文档，输出 OpenAPI 3 格式
+func (c *Capture) Merge(spec []byte) ([]byte, error) {
+	doc := normalizeSpec(spec)
+	if doc == nil {
+		return nil, fmt.Errorf("解析文档失败")
+	}
+	c.mergeInto(doc, false)
+	return json.Marshal(doc)
+}
+
+// WriteSpec merges observed examples into a spec file in place, keeping its format
+// 将采集到的示例写回文档文件（保持 Swagger 2.0 / OpenAPI 3 格式），便于提交
+func (c *Capture) WriteSpec(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc := parseSpec(data)
+	if doc == nil {
+		return fmt.Errorf("解析文档失败: %s", path)
+	}
+	c.mergeInto(doc, detectSpecFormat(data) == "swagger2")
+	out, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, out)
+}
+
+// mergeInto 将采集结果写入文档；Swagger 2.0 只保留每个响应最近一次的示例
+func (c *Capture) mergeInto(doc map[string]interface{}, swagger2 bool) {
+	exchanges := c.Exchanges()
+	keys := make([]string, 0, len(exchanges))
+	for k := range exchanges {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, key := range keys {
+		method, path, _ := strings.Cut(key, " ")
+		item, _ := paths[path].(map[string]interface{})
+		op, _ := item[strings.ToLower(method)].(map[string]interface{})
+		if op == nil {
+			continue
+		}
+		for i, ex := range exchanges[key] {
+			name := fmt.Sprintf("observed-%d", i+1)
+			summary := fmt.Sprintf("%s %s %s → %d", ex.Time.Format("2006-01-02 15:04:05"), ex.Method, ex.URL, ex.Status)
+			if swagger2 {
+				mergeSwagger2Example(doc, op, ex)
+				continue
+			}
+			if ex.ResponseBody != nil {
+				response := observedResponse(doc, op, ex.Status)
+				addObservedExample(response, ex.ResponseType, name, summary, ex.ResponseBody)
+			}
+			if ex.RequestBody != nil {
+				body := operationRequestBody(doc, op)
+				if body == nil {
+					body = map[string]interface{}{}
+				}
+				body = copyMap(body)
+				op["requestBody"] = body
+				addObservedExample(body, ex.RequestType, name, summary, ex.RequestBody)
+			}
+		}
+	}
+}
+
+// observedResponse 返回操作中对应状态码的响应（引用的响应会被复制，避免修改共享定义），不存在时新建
+func observedResponse(doc, op map[string]interface{}, status int) map[string]interface{} {
+	responses, ok := op["responses"].(map[string]interface{})
+	if !ok {
+		responses = make(map[string]interface{})
+		op["responses"] = responses
+	}
+	code := fmt.Sprint(status)
+	response, _ := responses[code].(map[string]interface{})
+	if ref := getString(response, "$ref"); ref != "" {
+		response = resolveRef(doc, ref)
+	}
+	if response == nil {
+		response = map[string]interface{}{"description": "Observed"}
+	}
+	response = copyMap(response)
+	responses[code] = response
+	return response
+}
+
+// addObservedExample 在 content 对应媒体类型下追加示例，媒体类型不存在时按示例推断 schema
+func addObservedExample(target map[string]interface{}, contentType, name, summary string, value interface{}) {
+	mediaType := "application/json"
+	if mt := strings.TrimSpace(strings.Split(contentType, ";")[0]); mt != "" {
+		mediaType = mt
+	}
+	content, ok := target["content"].(map[string]interface{})
+	if !ok {
+		content = make(map[string]interface{})
+	} else {
+		content = copyMap(content)
+	}
+	target["content"] = content
+	key, media := pickMediaType(content, mediaType)
+	if media == nil {
+		key, media = mediaType, map[string]interface{}{"schema": inferSchema(value)}
+	}
+	media = copyMap(media)
+	content[key] = media
+	examples, ok := media["examples"].(map[string]interface{})
+	if !ok {
+		examples = make(map[string]interface{})
+	} else {
+		examples = copyMap(examples)
+	}
+	media["examples"] = examples
+	examples[name] = map[string]interface{}{"summary": summary, "value": value, "x-observed": true}
+}
+
+// mergeSwagger2Example 以 Swagger 2.0 格式写入示例：响应的 examples[mime] 与请求体参数的 x-example
+func mergeSwagger2Example(doc, op map[string]interface{}, ex CapturedExchange) {
+	if ex.ResponseBody != nil {
+		response := observedResponse(doc, op, ex.Status)
+		if _, ok := response["schema"]; !ok {
+			response["schema"] = inferSchema(ex.ResponseBody)
+		}
+		mediaType := strings.TrimSpace(strings.Split(ex.ResponseType, ";")[0])
+		if mediaType == "" {
+			mediaType = "application/json"
+		}
+		examples, _ := response["examples"].(map[string]interface{})
+		examples = copyMap(examples)
+		examples[mediaType] = ex.ResponseBody
+		response["examples"] = examples
+	}
+	if ex.RequestBody != nil {
+		params, _ := op["parameters"].([]interface{})
+		for i, p := range params {
+			if param, ok := p.(map[string]interface{}); ok && getString(param, "in") == "body" {
+				param = copyMap(param)
+				param["x-example"] = ex.RequestBody
+				params[i] = param
+			}
+		}
+	}
+}
+
+// inferSchema 根据示例值推断 schema
+func inferSchema(v interface{}) map[string]interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		props := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			props[k] = inferSchema(item)
+		}
+		return map[string]interface{}{"type": "object", "properties": props}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array", "items": map[string]interface{}{}}
+		if len(val) > 0 {
+			schema["items"] = inferSchema(val[0])
+		}
+		return schema
+	case float64:
+		if val == float64(int64(val)) {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	}
+	return map[string]interface{}{"nullable": true}
+}
+
+// copyMap 浅拷贝 map，nil 时返回空 map
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免写入中断导致文件损坏
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// teeWriter 在写出响应的同时保留响应体副本
+type teeWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (t *teeWriter) WriteHeader(status int) {
+	if t.status == 0 {
+		t.status = status
+	}
+	t.ResponseWriter.WriteHeader(status)
+}
+
+func (t *teeWriter) Write(b []byte) (int, error) {
+	if t.status == 0 {
+		t.status = http.StatusOK
+	}
+	t.tee(b)
+	return t.ResponseWriter.Write(b)
+}
+
+func (t *teeWriter) tee(b []byte) {
+	if t.overflow {
+		return
+	}
+	if int64(t.body.Len()+len(b)) > t.limit {
+		t.overflow = true
+		return
+	}
+	t.body.Write(b)
+}
+
+func (t *teeWriter) Flush() {
+	if f, ok := t.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问原始 ResponseWriter
+func (t *teeWriter) Unwrap() http.ResponseWriter { return t.ResponseWriter }
+
+// ginTeeWriter Gin 版本的 teeWriter
+type ginTeeWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (t *ginTeeWriter) Write(b []byte) (int, error) {
+	t.tee(b)
+	return t.ResponseWriter.Write(b)
+}
+
+func (t *ginTeeWriter) WriteString(s string) (int, error) {
+	t.tee([]byte(s))
+	return t.ResponseWriter.WriteString(s)
+}
+
+func (t *ginTeeWriter) tee(b []byte) {
+	if t.overflow {
+		return
+	}
+	if int64(t.body.Len()+len(b)) > t.limit {
+		t.overflow = true
+		return
+	}
+	t.body.Write(b)
+}
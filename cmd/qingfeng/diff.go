@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/buyfakett/qingfeng"
+)
+
+// runDiff 执行 diff 子命令，存在破坏性变更时返回 1
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := fs.String("format", "markdown", "输出格式：markdown、json")
+	output := fs.String("o", "", "输出文件，默认输出到标准输出")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: qingfeng diff [参数] <旧版本 swagger.json|URL> <新版本 swagger.json|URL>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	oldSpec, _, err := readSpec(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	newSpec, _, err := readSpec(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	diff, err := qingfeng.DiffSpecs(oldSpec, newSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer f.Close()
+		out = f
+	}
+	switch strings.ToLower(*format) {
+	case "", "markdown", "md":
+		_, err = io.WriteString(out, diff.Markdown())
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(diff)
+	default:
+		err = fmt.Errorf("不支持的输出格式: %s", *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if diff.HasBreaking() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/buyfakett/qingfeng/lint"
+)
+
+// runLint 执行 lint 子命令，存在不低于 -fail-on 级别的问题时返回 1
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	format := fs.String("format", "text", "输出格式：text、json、sarif")
+	configPath := fs.String("config", "", "规则配置文件（JSON）")
+	failOn := fs.String("fail-on", "error", "达到该级别时以非 0 退出：error、warning、info")
+	output := fs.String("o", "", "输出文件，默认输出到标准输出")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: qingfeng lint [参数] <swagger.json|openapi.yaml|URL>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	switch lint.Severity(*failOn) {
+	case lint.SeverityError, lint.SeverityWarning, lint.SeverityInfo:
+	default:
+		fmt.Fprintf(os.Stderr, "不支持的 -fail-on 级别: %s\n\n", *failOn)
+		fs.Usage()
+		return 2
+	}
+	source := fs.Arg(0)
+
+	var cfg lint.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = lint.LoadConfig(*configPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+	spec, converted, err := readSpec(source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	report, err := lint.Run(spec, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if converted {
+		// 行号按转换后的 JSON 计算，对 YAML 原文件没有意义
+		for i := range report.Findings {
+			report.Findings[i].Line = 0
+		}
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := report.Write(out, *format, source); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if report.Failed(lint.Severity(*failOn)) {
+		return 1
+	}
+	return 0
+}
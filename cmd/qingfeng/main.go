@@ -0,0 +1,79 @@
+// Command qingfeng 青锋命令行工具
+//
+// 用法:
+//
+//	qingfeng lint [-format text|json|sarif] [-config lint.json] [-fail-on error|warning|info] <swagger.json|openapi.yaml|URL>
+//	qingfeng diff [-format markdown|json] <old.json|URL> <new.json|URL>
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/buyfakett/qingfeng"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var code int
+	switch os.Args[1] {
+	case "lint":
+		code = runLint(os.Args[2:])
+	case "diff":
+		code = runDiff(os.Args[2:])
+	case "-h", "--help", "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", os.Args[1])
+		usage()
+		code = 2
+	}
+	os.Exit(code)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `青锋命令行工具
+
+用法:
+  qingfeng <命令> [参数]
+
+命令:
+  lint    检查文档质量（text/JSON/SARIF 报告）
+  diff    对比两个版本的文档，存在破坏性变更时以 1 退出
+
+使用 "qingfeng <命令> -h" 查看命令参数`)
+}
+
+// readSpec 读取文档：本地文件或 http(s) 地址（如正在运行的服务的 /doc/openapi.json）。
+// 与 Config.DocPath 一样支持 YAML：非 JSON 的文档经 qingfeng.BundleSpec 转换为 JSON，
+// 此时 converted 为 true，检查结果中的行号无法对应原文件
+func readSpec(source string) (data []byte, converted bool, err error) {
+	if data, err = readSource(source); err != nil || json.Valid(data) {
+		return data, false, err
+	}
+	data, err = qingfeng.BundleSpec(source)
+	return data, true, err
+}
+
+// readSource 读取本地文件或 http(s) 地址的原始内容
+func readSource(source string) ([]byte, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		resp, err := http.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("获取文档失败: %s", resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+	return os.ReadFile(source)
+}
@@ -0,0 +1,294 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// registry 代码优先声明的接口
+var registry struct {
+	mu  sync.Mutex
+	ops []*Operation
+}
+
+// Operation is a code-first operation declaration
+// 代码优先的接口声明，通过 Op 创建并链式补充信息：
+//
+//	qingfeng.Op("GET", "/users/{id}").
+//		Summary("获取用户").
+//		Tags("User").
+//		Query("verbose", false).
+//		Returns(200, User{}).
+//		Returns(404, ErrorResponse{})
+//
+// 声明的接口会合并进青锋提供的文档（与注释生成的同名接口冲突时以代码声明为准），
+// 请求/响应类型通过反射生成 JSON Schema，支持 json、example、enums、description、format、default
+// 以及 binding/validate（required、min、max、len、oneof、email 等）标签。
+type Operation struct {
+	mu          sync.Mutex
+	method      string
+	path        string
+	summary     string
+	description string
+	operationID string
+	tags        []string
+	deprecated  bool
+	params      []opParam
+	body        *opContent
+	responses   map[int]opContent
+}
+
+type opParam struct {
+	in, name    string
+	typ         interface{}
+	required    bool
+	description string
+}
+
+type opContent struct {
+	value       interface{}
+	contentType string
+	description string
+}
+
+// Op declares an operation and registers it for all QingFeng handlers
+// 声明一个接口并注册到全局，path 为文档中的路径（相对 basePath/servers），如 "/users/{id}"
+func Op(method, path string) *Operation {
+	op := &Operation{method: strings.ToLower(method), path: path, responses: make(map[int]opContent)}
+	registry.mu.Lock()
+	registry.ops = append(registry.ops, op)
+	registry.mu.Unlock()
+	return op
+}
+
+// Summary sets the operation summary (接口摘要)
+func (o *Operation) Summary(s string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.summary = s
+	return o
+}
+
+// Description sets the operation description, Markdown supported (接口描述，支持 Markdown)
+func (o *Operation) Description(s string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.description = s
+	return o
+}
+
+// ID sets the operationId (操作 ID)
+func (o *Operation) ID(id string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.operationID = id
+	return o
+}
+
+// Tags sets the operation tags (分组标签)
+func (o *Operation) Tags(tags ...string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tags = append(o.tags, tags...)
+	return o
+}
+
+// Deprecated marks the operation as deprecated (标记为已废弃)
+func (o *Operation) Deprecated() *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.deprecated = true
+	return o
+}
+
+// Path declares the type of a path parameter; parameters in the path template default to string
+// 声明路径参数类型，未声明的路径参数默认为 string
+func (o *Operation) Path(name string, typ interface{}, description ...string) *Operation {
+	return o.param("path", name, typ, true, description)
+}
+
+// Query declares an optional query parameter, typ is a sample value such as int(0) or []string{}
+// 声明可选查询参数，typ 为示例值（用于推断类型），如 int(0)、[]string{}
+func (o *Operation) Query(name string, typ interface{}, description ...string) *Operation {
+	return o.param("query", name, typ, false, description)
+}
+
+// RequiredQuery declares a required query parameter (声明必填查询参数)
+func (o *Operation) RequiredQuery(name string, typ interface{}, description ...string) *Operation {
+	return o.param("query", name, typ, true, description)
+}
+
+// Header declares a request header parameter (声明请求头参数)
+func (o *Operation) Header(name string, typ interface{}, required bool, description ...string) *Operation {
+	return o.param("header", name, typ, required, description)
+}
+
+func (o *Operation) param(in, name string, typ interface{}, required bool, description []string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.params = append(o.params, opParam{in: in, name: name, typ: typ, required: required, description: strings.Join(description, " ")})
+	return o
+}
+
+// Body declares a required JSON request body (声明 JSON 请求体)
+func (o *Operation) Body(v interface{}) *Operation {
+	return o.BodyAs("application/json", v)
+}
+
+// BodyAs declares a request body with the given content type, e.g. "multipart/form-data"
+// 声明指定 Content-Type 的请求体
+func (o *Operation) BodyAs(contentType string, v interface{}) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.body = &opContent{value: v, contentType: contentType}
+	return o
+}
+
+// Returns declares a JSON response; v is nil for responses without a body
+// 声明 JSON 响应，v 为 nil 表示无响应体
+func (o *Operation) Returns(code int, v interface{}, description ...string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.responses[code] = opContent{value: v, contentType: "application/json", description: strings.Join(description, " ")}
+	return o
+}
+
+// build 生成 OpenAPI 3 操作对象
+func (o *Operation) build(b *schemaBuilder) map[string]interface{} {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	op := map[string]interface{}{}
+	if o.summary != "" {
+		op["summary"] = o.summary
+	}
+	if o.description != "" {
+		op["description"] = o.description
+	}
+	if o.operationID != "" {
+		op["operationId"] = o.operationID
+	}
+	if len(o.tags) > 0 {
+		op["tags"] = o.tags
+	}
+	if o.deprecated {
+		op["deprecated"] = true
+	}
+
+	// 路径模板中的参数默认为 string，可被 Path 声明覆盖
+	declared := make(map[string]bool)
+	for _, p := range o.params {
+		declared[p.in+":"+p.name] = true
+	}
+	var params []interface{}
+	for _, part := range strings.Split(o.path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") && !declared["path:"+part[1:len(part)-1]] {
+			params = append(params, map[string]interface{}{
+				"name": part[1 : len(part)-1], "in": "path", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+	}
+	for _, p := range o.params {
+		param := map[string]interface{}{"name": p.name, "in": p.in, "required": p.required}
+		if schema := b.schemaOf(p.typ); schema != nil {
+			param["schema"] = schema
+		} else {
+			param["schema"] = map[string]interface{}{"type": "string"}
+		}
+		if p.description != "" {
+			param["description"] = p.description
+		}
+		params = append(params, param)
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if o.body != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  map[string]interface{}{o.body.contentType: map[string]interface{}{"schema": b.schemaOf(o.body.value)}},
+		}
+	}
+
+	responses := make(map[string]interface{})
+	codes := make([]int, 0, len(o.responses))
+	for code := range o.responses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		r := o.responses[code]
+		desc := r.description
+		if desc == "" {
+			desc = http.StatusText(code)
+		}
+		resp := map[string]interface{}{"description": desc}
+		if schema := b.schemaOf(r.value); schema != nil {
+			resp["content"] = map[string]interface{}{r.contentType: map[string]interface{}{"schema": schema}}
+		}
+		responses[strconv.Itoa(code)] = resp
+	}
+	if len(responses) == 0 {
+		responses["200"] = map[string]interface{}{"description": "OK"}
+	}
+	op["responses"] = responses
+	return op
+}
+
+// registeredOperations 返回已注册的代码优先接口
+func registeredOperations() []*Operation {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return append([]*Operation(nil), registry.ops...)
+}
+
+// MergeOperations merges code-first operations into spec (nil for an empty document)
+// 将代码优先声明的接口合并进文档（Swagger 2.0 会先转换为 OpenAPI 3），ops 为空时使用 Op 注册的全部接口
+func MergeOperations(cfg Config, spec []byte, ops ...*Operation) ([]byte, error) {
+	if len(ops) == 0 {
+		ops = registeredOperations()
+	}
+	doc := normalizeSpec(spec)
+	if doc == nil {
+		doc = newBaseSpec(cfg)
+	}
+
+	components, ok := doc["components"].(map[string]interface{})
+	if !ok {
+		components = make(map[string]interface{})
+		doc["components"] = components
+	}
+	schemas, ok := components["schemas"].(map[string]interface{})
+	if !ok {
+		schemas = make(map[string]interface{})
+		components["schemas"] = schemas
+	}
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		paths = make(map[string]interface{})
+		doc["paths"] = paths
+	}
+
+	b := newSchemaBuilder(schemas)
+	for _, op := range ops {
+		item, ok := paths[op.path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[op.path] = item
+		}
+		item[op.method] = op.build(b)
+	}
+	if len(schemas) == 0 {
+		delete(components, "schemas")
+	}
+	if len(components) == 0 {
+		delete(doc, "components")
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,208 @@
+package qingfeng
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route is a route registered in the application
+// 应用中注册的路由
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Handler is the handler function name, used for introspected summaries (处理器名称，可选)
+	Handler string `json:"handler,omitempty"`
+}
+
+// CoverageOptions configures Coverage
+// 路由覆盖率检查配置
+type CoverageOptions struct {
+	// Prefixes are extra path prefixes stripped from routes before matching, e.g. "/api/v1"
+	// 匹配前额外去掉的路由前缀（文档 basePath/servers 会自动处理）
+	Prefixes []string
+	// Ignore skips routes under these path prefixes, e.g. "/doc", "/metrics"
+	// 忽略以这些前缀开头的路由
+	Ignore []string
+}
+
+// MethodMismatch is a documented path whose registered methods differ from the spec
+// 路径已在文档中声明，但注册的方法与文档不一致
+type MethodMismatch struct {
+	Path       string   `json:"path"`
+	Documented []string `json:"documented"` // 仅在文档中声明的方法
+	Registered []string `json:"registered"` // 仅在应用中注册的方法
+}
+
+// CoverageReport is the diff between registered routes and documented operations
+// 路由覆盖率报告
+type CoverageReport struct {
+	// Routes 参与检查的路由数量
+	Routes int `json:"routes"`
+	// Operations 文档中声明的操作数量
+	Operations int `json:"operations"`
+	// Documented 已在文档中声明的路由数量
+	Documented int `json:"documented"`
+	// Coverage 已文档化路由的百分比
+	Coverage float64 `json:"coverage"`
+	// Undocumented 已注册但未在文档中声明的路由
+	Undocumented []Route `json:"undocumented"`
+	// Missing 文档中声明但未注册的操作
+	Missing []Route `json:"missing"`
+	// MethodMismatches 路径一致但方法不一致的接口
+	MethodMismatches []MethodMismatch `json:"methodMismatches"`
+}
+
+// Ok reports whether routes and spec are fully in sync (路由与文档完全一致)
+func (r *CoverageReport) Ok() bool {
+	return len(r.Undocumented) == 0 && len(r.Missing) == 0 && len(r.MethodMismatches) == 0
+}
+
+// Err returns a descriptive error when routes and spec drift, for use in tests/CI
+// 路由与文档不一致时返回错误，便于在测试中使用
+func (r *CoverageReport) Err() error {
+	if r.Ok() {
+		return nil
+	}
+	var lines []string
+	for _, route := range r.Undocumented {
+		lines = append(lines, fmt.Sprintf("未文档化: %s %s", route.Method, route.Path))
+	}
+	for _, route := range r.Missing {
+		lines = append(lines, fmt.Sprintf("未注册: %s %s", route.Method, route.Path))
+	}
+	for _, m := range r.MethodMismatches {
+		lines = append(lines, fmt.Sprintf("方法不一致: %s 文档 %v 注册 %v", m.Path, m.Documented, m.Registered))
+	}
+	return fmt.Errorf("路由覆盖率 %.1f%%，发现 %d 处不一致:\n  %s", r.Coverage, len(lines), strings.Join(lines, "\n  "))
+}
+
+// GinRoutes converts gin's Engine.Routes() to []Route
+// 将 Gin 的 Engine.Routes() 转换为路由列表
+func GinRoutes(routes gin.RoutesInfo) []Route {
+	result := make([]Route, 0, len(routes))
+	for _, r := range routes {
+		result = append(result, Route{Method: r.Method, Path: r.Path, Handler: r.Handler})
+	}
+	return result
+}
+
+// Coverage diffs the application's routes against the spec
+// 对比应用注册的路由与文档中的操作，报告未文档化的路由、未注册的操作与方法不一致。
+//
+// 路由路径支持 Gin/Echo 的 :id、*any，Chi 的 {id}、{id:[0-9]+} 与 * 写法，参数名不要求与文档一致。
+//
+// 获取路由列表:
+//   - Gin: qingfeng.GinRoutes(r.Routes())
+//   - Echo: 遍历 e.Routes() 取 Method 与 Path
+//   - Chi: chi.Walk(r, func(method, route string, ...) error { ... })
+func Coverage(spec []byte, routes []Route, opts ...CoverageOptions) *CoverageReport {
+	var opt CoverageOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	doc := normalizeSpec(spec)
+	report := &CoverageReport{Undocumented: []Route{}, Missing: []Route{}, MethodMismatches: []MethodMismatch{}}
+
+	// 文档路径按结构归一化（参数统一为 {}）
+	documented := make(map[string]map[string]bool) // 文档路径 -> 方法
+	shapes := make(map[string]string)              // 归一化路径 -> 文档路径
+	forEachOperation(doc, func(path, method string, op map[string]interface{}) {
+		if documented[path] == nil {
+			documented[path] = make(map[string]bool)
+			shapes[routeShape(path)] = path
+		}
+		documented[path][strings.ToUpper(method)] = true
+		report.Operations++
+	})
+
+	registered := make(map[string]map[string]bool) // 文档路径 -> 已注册方法
+	seen := make(map[Route]bool)
+	for _, route := range routes {
+		method := strings.ToUpper(route.Method)
+		if !isHTTPMethod(method) || ignoredRoute(route.Path, opt.Ignore) {
+			continue
+		}
+		key := Route{Method: method, Path: route.Path}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		report.Routes++
+
+		docPath := ""
+		for _, candidate := range pathCandidates(doc, route.Path, opt.Prefixes) {
+			if p, ok := shapes[routeShape(candidate)]; ok {
+				docPath = p
+				break
+			}
+		}
+		if docPath == "" {
+			report.Undocumented = append(report.Undocumented, key)
+			continue
+		}
+		if registered[docPath] == nil {
+			registered[docPath] = make(map[string]bool)
+		}
+		registered[docPath][method] = true
+		if documented[docPath][method] {
+			report.Documented++
+		}
+	}
+
+	paths := make([]string, 0, len(documented))
+	for p := range documented {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		reg := registered[p]
+		var onlyDoc, onlyReg []string
+		for _, m := range httpMethods {
+			m = strings.ToUpper(m)
+			switch {
+			case documented[p][m] && !reg[m]:
+				onlyDoc = append(onlyDoc, m)
+			case reg[m] && !documented[p][m]:
+				onlyReg = append(onlyReg, m)
+			}
+		}
+		if len(reg) > 0 && len(onlyReg) > 0 {
+			report.MethodMismatches = append(report.MethodMismatches, MethodMismatch{Path: p, Documented: onlyDoc, Registered: onlyReg})
+			continue
+		}
+		for _, m := range onlyDoc {
+			report.Missing = append(report.Missing, Route{Method: m, Path: p})
+		}
+	}
+
+	if report.Routes > 0 {
+		report.Coverage = float64(report.Documented) * 100 / float64(report.Routes)
+	}
+	return report
+}
+
+// routeShape 将路由/文档路径归一化：路径参数与通配符统一为 {}
+// （与 routeTemplate 一致，*filepath 在文档中为 {filepath}）
+func routeShape(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") || strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			parts[i] = "{}"
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// ignoredRoute 判断路由是否在忽略的前缀下
+func ignoredRoute(path string, ignore []string) bool {
+	for _, prefix := range ignore {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix != "" && (path == prefix || strings.HasPrefix(path, prefix+"/")) {
+			return true
+		}
+	}
+	return false
+}
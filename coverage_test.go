@@ -0,0 +1,50 @@
+package qingfeng
+
+import "testing"
+
+const coverageSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "files", "version": "1.0.0"},
+  "servers": [{"url": "/api"}],
+  "paths": {
+    "/users/{id}": {"get": {"responses": {"200": {"description": "ok"}}}, "delete": {"responses": {"204": {"description": "ok"}}}},
+    "/files/{filepath}": {"get": {"responses": {"200": {"description": "ok"}}}},
+    "/reports": {"get": {"responses": {"200": {"description": "ok"}}}}
+  }
+}`
+
+func TestCoverageCatchAll(t *testing.T) {
+	routes := []Route{
+		{Method: "GET", Path: "/api/users/:id"},
+		{Method: "DELETE", Path: "/api/users/:id"},
+		{Method: "GET", Path: "/api/files/*filepath"},
+		{Method: "GET", Path: "/api/reports"},
+		{Method: "GET", Path: "/doc/*filepath"},
+	}
+	report := Coverage([]byte(coverageSpec), routes, CoverageOptions{Ignore: []string{"/doc"}})
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if report.Routes != 4 || report.Documented != 4 || report.Coverage != 100 {
+		t.Errorf("报告 = %+v", report)
+	}
+}
+
+func TestCoverageDrift(t *testing.T) {
+	routes := []Route{
+		{Method: "GET", Path: "/api/users/:id"},
+		{Method: "PUT", Path: "/api/users/:id"},
+		{Method: "GET", Path: "/api/files/*"},
+		{Method: "POST", Path: "/api/orders"},
+	}
+	report := Coverage([]byte(coverageSpec), routes)
+	if len(report.Undocumented) != 1 || report.Undocumented[0].Path != "/api/orders" {
+		t.Errorf("未文档化 = %v", report.Undocumented)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].Path != "/reports" {
+		t.Errorf("未注册 = %v", report.Missing)
+	}
+	if len(report.MethodMismatches) != 1 || report.MethodMismatches[0].Path != "/users/{id}" {
+		t.Errorf("方法不一致 = %+v", report.MethodMismatches)
+	}
+}
@@ -0,0 +1,316 @@
+package qingfeng
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// CurlFormField 表示 cURL -F 指定的 multipart 字段
+type CurlFormField struct {
+	// Name 字段名
+	Name string `json:"name"`
+	// Value 字段值（文件字段为空）
+	Value string `json:"value,omitempty"`
+	// File 文件名（-F 'file=@avatar.png' 中 @ 之后的部分）
+	File string `json:"file,omitempty"`
+}
+
+// CurlCommand 解析后的 cURL 命令
+type CurlCommand struct {
+	// Method 请求方法（大写）
+	Method string `json:"method"`
+	// URL 完整请求地址
+	URL string `json:"url"`
+	// Path 请求路径（不含查询参数）
+	Path string `json:"path"`
+	// Query 查询参数
+	Query map[string][]string `json:"query,omitempty"`
+	// Headers 请求头（-u、--cookie 会转换为 Authorization、Cookie 头）
+	Headers []Header `json:"headers,omitempty"`
+	// Body 请求体（-d/--data-raw 等）
+	Body string `json:"body,omitempty"`
+	// Form multipart 表单字段（-F）
+	Form []CurlFormField `json:"form,omitempty"`
+}
+
+// curlIgnoredArgFlags 带参数但与请求内容无关的 cURL 选项
+var curlIgnoredArgFlags = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true, "--connect-timeout": true,
+	"-x": true, "--proxy": true, "--cacert": true, "--cert": true, "--key": true,
+	"-w": true, "--write-out": true, "--retry": true, "-c": true, "--cookie-jar": true,
+	"--resolve": true, "--limit-rate": true,
+}
+
+// curlArgFlags 带参数的短选项，支持 -XPOST、-H'Key: Value' 这类紧凑写法
+var curlArgFlags = "XHdFubAeomxwc"
+
+// ParseCurl 解析 cURL 命令（如浏览器 "Copy as cURL" 或 Bug 报告中的命令）
+// 支持 -X、-H、-d/--data-raw/--data-binary、--data-urlencode、-F、-u、--cookie、-G 等常用选项，
+// 以及 -sX POST 这类组合的短选项
+func ParseCurl(command string) (*CurlCommand, error) {
+	args, err := splitShellArgs(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && (args[0] == "curl" || strings.HasSuffix(args[0], "/curl")) {
+		args = args[1:]
+	}
+
+	cmd := &CurlCommand{}
+	var rawURL string
+	var data []string
+	var forceGet, head bool
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		// 展开组合的短选项：-sX POST → -s -X POST，-sSLXPOST → -s -S -L -XPOST
+		if expanded := expandShortFlags(arg); expanded != nil {
+			args = append(args[:i], append(expanded, args[i+1:]...)...)
+			arg = args[i]
+		}
+		name, value, hasValue := arg, "", false
+
+		// 展开 -XPOST / --request=POST 形式
+		if strings.HasPrefix(arg, "--") {
+			if idx := strings.Index(arg, "="); idx > 0 {
+				name, value, hasValue = arg[:idx], arg[idx+1:], true
+			}
+		} else if strings.HasPrefix(arg, "-") && len(arg) > 2 && strings.ContainsRune(curlArgFlags, rune(arg[1])) {
+			name, value, hasValue = arg[:2], arg[2:], true
+		}
+
+		next := func() string {
+			if hasValue {
+				return value
+			}
+			if i+1 < len(args) {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+
+		switch name {
+		case "-X", "--request":
+			cmd.Method = strings.ToUpper(next())
+		case "-H", "--header":
+			if key, val, ok := strings.Cut(next(), ":"); ok {
+				cmd.Headers = append(cmd.Headers, Header{Key: strings.TrimSpace(key), Value: strings.TrimSpace(val)})
+			}
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii":
+			data = append(data, next())
+		case "--data-urlencode":
+			data = append(data, curlURLEncode(next()))
+		case "--json":
+			data = append(data, next())
+			cmd.Headers = append(cmd.Headers, Header{Key: "Content-Type", Value: "application/json"})
+		case "-F", "--form", "--form-string":
+			key, val, _ := strings.Cut(next(), "=")
+			field := CurlFormField{Name: key}
+			if name != "--form-string" && strings.HasPrefix(val, "@") {
+				field.File = strings.SplitN(val[1:], ";", 2)[0]
+			} else {
+				field.Value = val
+			}
+			cmd.Form = append(cmd.Form, field)
+		case "-u", "--user":
+			cred := base64.StdEncoding.EncodeToString([]byte(next()))
+			cmd.Headers = append(cmd.Headers, Header{Key: "Authorization", Value: "Basic " + cred})
+		case "-b", "--cookie":
+			// 不含 '=' 时 cURL 将其视为 cookie 文件，忽略
+			if v := next(); strings.Contains(v, "=") {
+				cmd.Headers = append(cmd.Headers, Header{Key: "Cookie", Value: v})
+			}
+		case "-A", "--user-agent":
+			cmd.Headers = append(cmd.Headers, Header{Key: "User-Agent", Value: next()})
+		case "-e", "--referer":
+			cmd.Headers = append(cmd.Headers, Header{Key: "Referer", Value: next()})
+		case "--url":
+			rawURL = next()
+		case "-G", "--get":
+			forceGet = true
+		case "-I", "--head":
+			head = true
+		default:
+			if curlIgnoredArgFlags[name] {
+				next()
+				continue
+			}
+			if strings.HasPrefix(arg, "-") {
+				continue // 其他无参数选项（-s、-k、-L、--compressed 等）
+			}
+			if rawURL == "" {
+				rawURL = arg
+			}
+		}
+	}
+
+	if rawURL == "" {
+		return nil, errors.New("cURL 命令中未找到请求地址")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.Join(data, "&")
+	if forceGet && body != "" {
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += body
+		body = ""
+	}
+
+	switch {
+	case cmd.Method != "":
+	case head:
+		cmd.Method = "HEAD"
+	case forceGet:
+		cmd.Method = "GET"
+	case body != "" || len(cmd.Form) > 0:
+		cmd.Method = "POST"
+	default:
+		cmd.Method = "GET"
+	}
+
+	cmd.URL = u.String()
+	cmd.Path = u.Path
+	if cmd.Path == "" {
+		cmd.Path = "/"
+	}
+	if q := u.Query(); len(q) > 0 {
+		cmd.Query = q
+	}
+	cmd.Body = body
+	return cmd, nil
+}
+
+// expandShortFlags 将组合的短选项拆分为单个选项，遇到带参数的选项时其余部分作为参数值；
+// 不是组合选项（如 -XPOST、--data、-s）时返回 nil
+func expandShortFlags(arg string) []string {
+	if len(arg) <= 2 || arg[0] != '-' || arg[1] == '-' || strings.ContainsRune(curlArgFlags, rune(arg[1])) {
+		return nil
+	}
+	var flags []string
+	for j := 1; j < len(arg); j++ {
+		c := arg[j]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return nil
+		}
+		if strings.ContainsRune(curlArgFlags, rune(c)) {
+			return append(flags, "-"+arg[j:])
+		}
+		flags = append(flags, "-"+string(c))
+	}
+	return flags
+}
+
+// curlURLEncode 按 cURL --data-urlencode 的规则编码："content"、"=content" 编码整体，
+// "name=content" 只编码 = 之后的部分；"@file"、"name@file" 需要读取文件，原样保留
+func curlURLEncode(v string) string {
+	escape := func(s string) string {
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	}
+	eq, at := strings.Index(v, "="), strings.Index(v, "@")
+	switch {
+	case at >= 0 && (eq < 0 || at < eq):
+		return v
+	case eq == 0:
+		return escape(v[1:])
+	case eq > 0:
+		return v[:eq+1] + escape(v[eq+1:])
+	}
+	return escape(v)
+}
+
+// splitShellArgs 按 POSIX shell 规则切分命令行参数
+// 支持单引号、双引号、$'...' 以及反斜杠续行
+func splitShellArgs(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			if i+1 < len(s) {
+				i++
+				if s[i] == '\n' || s[i] == '\r' {
+					// 续行
+					if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+						i++
+					}
+					continue
+				}
+				cur.WriteByte(s[i])
+				inArg = true
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("单引号未闭合")
+			}
+			cur.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case c == '$' && i+1 < len(s) && s[i+1] == '\'':
+			i += 2
+			for ; i < len(s) && s[i] != '\''; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					switch s[i] {
+					case 'n':
+						cur.WriteByte('\n')
+					case 't':
+						cur.WriteByte('\t')
+					case 'r':
+						cur.WriteByte('\r')
+					default:
+						cur.WriteByte(s[i])
+					}
+					continue
+				}
+				cur.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, errors.New("$'...' 引号未闭合")
+			}
+			inArg = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				cur.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, errors.New("双引号未闭合")
+			}
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
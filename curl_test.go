@@ -0,0 +1,38 @@
+package qingfeng
+
+import "testing"
+
+func TestParseCurl(t *testing.T) {
+	cases := []struct {
+		command string
+		method  string
+		url     string
+		body    string
+	}{
+		{`curl -sX POST https://api.example.com/users -d name=tom`, "POST", "https://api.example.com/users", "name=tom"},
+		{`curl -sSLXPUT https://api.example.com/users/1`, "PUT", "https://api.example.com/users/1", ""},
+		{`curl -kI api.example.com/health`, "HEAD", "http://api.example.com/health", ""},
+		{`curl https://api.example.com/users -d -1`, "POST", "https://api.example.com/users", "-1"},
+		{`curl https://api.example.com/search --data-urlencode "q=hello world&more" --data-urlencode "=a+b" --data-urlencode "file@q.txt"`,
+			"POST", "https://api.example.com/search", "q=hello%20world%26more&a%2Bb&file@q.txt"},
+		{`curl -sG https://api.example.com/search --data-urlencode "q=a b"`, "GET", "https://api.example.com/search?q=a%20b", ""},
+	}
+	for _, c := range cases {
+		cmd, err := ParseCurl(c.command)
+		if err != nil {
+			t.Errorf("%s: %v", c.command, err)
+			continue
+		}
+		if cmd.Method != c.method || cmd.URL != c.url || cmd.Body != c.body {
+			t.Errorf("%s:\n  得到 %s %s %q\n  期望 %s %s %q", c.command, cmd.Method, cmd.URL, cmd.Body, c.method, c.url, c.body)
+		}
+	}
+
+	cmd, err := ParseCurl(`curl -sH 'Authorization: Bearer t' -u admin:secret https://api.example.com/me`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmd.Headers) != 2 || cmd.Headers[0].Value != "Bearer t" || cmd.Headers[1].Value != "Basic YWRtaW46c2VjcmV0" {
+		t.Errorf("请求头 = %+v", cmd.Headers)
+	}
+}
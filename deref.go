@@ -0,0 +1,126 @@
+package qingfeng
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DereferenceSpec expands every internal $ref under paths (and webhooks) so clients never resolve refs
+// 预先展开 paths（及 webhooks）中的所有内部 $ref，前端与导出无需再逐层解析引用：
+//   - 展开的 schema 带有 x-qingfeng-ref 标记原引用（如 "#/components/schemas/User"），用于显示模型名称
+//   - 递归 schema 在第二次出现时输出 {"x-qingfeng-circular": "#/components/schemas/Node"}，不再继续展开
+//   - components / definitions 保持原样
+func DereferenceSpec(spec []byte) ([]byte, error) {
+	doc := parseSpec(spec)
+	if doc == nil {
+		return nil, fmt.Errorf("解析文档失败")
+	}
+	d := &dereferencer{doc: doc, memo: make(map[string]interface{})}
+	for _, key := range []string{"paths", "webhooks"} {
+		if v, ok := doc[key]; ok {
+			doc[key] = d.expand(v)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// dereferencer 展开引用的状态：memo 缓存已完整展开的引用，stack 为正在展开的引用链
+type dereferencer struct {
+	doc   map[string]interface{}
+	memo  map[string]interface{}
+	stack []string
+}
+
+func (d *dereferencer) expand(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+			resolved := d.expandRef(ref)
+			if len(val) == 1 {
+				return resolved
+			}
+			// OpenAPI 3.1 允许 $ref 旁出现 description 等字段，覆盖到展开结果上
+			target, ok := resolved.(map[string]interface{})
+			if !ok {
+				return resolved
+			}
+			merged := copyMap(target)
+			for k, item := range val {
+				if k != "$ref" {
+					merged[k] = d.expand(item)
+				}
+			}
+			return merged
+		}
+		result := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			result[k] = d.expand(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			result[i] = d.expand(item)
+		}
+		return result
+	}
+	return v
+}
+
+func (d *dereferencer) expandRef(ref string) interface{} {
+	for _, r := range d.stack {
+		if r == ref {
+			return map[string]interface{}{"x-qingfeng-circular": ref}
+		}
+	}
+	if cached, ok := d.memo[ref]; ok {
+		return cached
+	}
+	target := resolveRef(d.doc, ref)
+	if target == nil {
+		// 无法解析的引用保持原样
+		return map[string]interface{}{"$ref": ref}
+	}
+
+	d.stack = append(d.stack, ref)
+	expanded := d.expand(target)
+	d.stack = d.stack[:len(d.stack)-1]
+
+	if m, ok := expanded.(map[string]interface{}); ok && isSchemaRef(ref) {
+		// 别名引用（schema 本身只是另一个 $ref）的展开结果与目标共用，需复制后再标记
+		m = copyMap(m)
+		m["x-qingfeng-ref"] = ref
+		expanded = m
+	}
+	d.memo[ref] = expanded
+	return expanded
+}
+
+// isSchemaRef 判断引用是否指向 schema 定义
+func isSchemaRef(ref string) bool {
+	return strings.HasPrefix(ref, "#/components/schemas/") || strings.HasPrefix(ref, "#/definitions/")
+}
+
+// derefCache 缓存最近一次展开的结果，文档内容不变时直接复用
+type derefCache struct {
+	mu  sync.Mutex
+	src []byte
+	out []byte
+}
+
+func (c *derefCache) get(spec []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.out != nil && bytes.Equal(c.src, spec) {
+		return c.out, nil
+	}
+	out, err := DereferenceSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	c.src, c.out = spec, out
+	return out, nil
+}
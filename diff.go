@@ -0,0 +1,521 @@
+package qingfeng
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SpecChange is a single difference between two document versions
+// 两个文档版本之间的一处变更
+type SpecChange struct {
+	// Breaking 是否为破坏性变更（可能导致已有客户端出错）
+	Breaking bool `json:"breaking"`
+	// Type 变更类型，如 operation-removed、param-required-added、enum-narrowed
+	Type string `json:"type"`
+	// Operation 所属接口，如 "GET /users/{id}"
+	Operation string `json:"operation,omitempty"`
+	// Location 变更位置，如 "query.page"、"request.body.name"、"response.200.body.items[]"
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// SpecDiff is the result of comparing two documents
+// 文档对比结果
+type SpecDiff struct {
+	OldVersion string       `json:"oldVersion,omitempty"`
+	NewVersion string       `json:"newVersion,omitempty"`
+	Changes    []SpecChange `json:"changes"`
+}
+
+// HasBreaking reports whether any change is breaking (是否存在破坏性变更)
+func (d *SpecDiff) HasBreaking() bool {
+	for _, c := range d.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Markdown renders the diff as a changelog (以 Markdown 格式输出变更日志)
+func (d *SpecDiff) Markdown() string {
+	var b strings.Builder
+	title := "API 变更日志"
+	if d.OldVersion != "" || d.NewVersion != "" {
+		title += fmt.Sprintf("（%s → %s）", d.OldVersion, d.NewVersion)
+	}
+	b.WriteString("# " + title + "\n\n")
+	if len(d.Changes) == 0 {
+		b.WriteString("没有变更。\n")
+		return b.String()
+	}
+
+	var breaking, other []SpecChange
+	for _, c := range d.Changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		} else {
+			other = append(other, c)
+		}
+	}
+	section := func(heading string, changes []SpecChange) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "## %s（%d）\n\n", heading, len(changes))
+		for _, c := range changes {
+			b.WriteString("- ")
+			if c.Operation != "" {
+				fmt.Fprintf(&b, "`%s` ", c.Operation)
+			}
+			b.WriteString(c.Message)
+			if c.Location != "" {
+				fmt.Fprintf(&b, "（`%s`）", c.Location)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	section("⚠️ 破坏性变更", breaking)
+	section("其他变更", other)
+	return b.String()
+}
+
+// DiffSpecs compares two documents (Swagger 2.0 is normalized to OpenAPI 3 first) and classifies changes
+// 对比两个版本的文档并区分破坏性/非破坏性变更：
+// 删除接口、新增必填参数、枚举收窄、响应类型变化等会被标记为破坏性变更
+func DiffSpecs(oldSpec, newSpec []byte) (*SpecDiff, error) {
+	oldDoc, newDoc := normalizeSpec(oldSpec), normalizeSpec(newSpec)
+	if oldDoc == nil {
+		return nil, fmt.Errorf("解析旧版本文档失败")
+	}
+	if newDoc == nil {
+		return nil, fmt.Errorf("解析新版本文档失败")
+	}
+	d := &differ{old: oldDoc, new: newDoc, changes: []SpecChange{}}
+	d.diffOperations()
+
+	info := func(doc map[string]interface{}) string {
+		i, _ := doc["info"].(map[string]interface{})
+		return getString(i, "version")
+	}
+	return &SpecDiff{OldVersion: info(oldDoc), NewVersion: info(newDoc), Changes: d.changes}, nil
+}
+
+// differ 保存对比过程中的状态
+type differ struct {
+	old, new map[string]interface{}
+	changes  []SpecChange
+	opName   string
+	// refs 当前对比路径上的 $ref 组合（旧引用、新引用），再次出现即为循环引用，不再展开；
+	// 同一组件出现在不同位置（如 billing 与 shipping 都引用 Address）时各自对比
+	refs []string
+}
+
+func (d *differ) add(breaking bool, typ, location, format string, args ...interface{}) {
+	d.changes = append(d.changes, SpecChange{
+		Breaking:  breaking,
+		Type:      typ,
+		Operation: d.opName,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+type diffOperation struct {
+	path string
+	op   map[string]interface{}
+}
+
+// collectOperations 按 "METHOD 归一化路径" 收集操作，路径参数改名不视为变更
+func collectOperations(doc map[string]interface{}) (map[string]diffOperation, []string) {
+	ops := make(map[string]diffOperation)
+	var keys []string
+	forEachOperation(doc, func(path, method string, op map[string]interface{}) {
+		key := strings.ToUpper(method) + " " + routeShape(path)
+		ops[key] = diffOperation{path: path, op: op}
+		keys = append(keys, key)
+	})
+	return ops, keys
+}
+
+func (d *differ) diffOperations() {
+	oldOps, oldKeys := collectOperations(d.old)
+	newOps, newKeys := collectOperations(d.new)
+
+	for _, key := range oldKeys {
+		method, _, _ := strings.Cut(key, " ")
+		oldOp := oldOps[key]
+		d.opName = method + " " + oldOp.path
+		newOp, ok := newOps[key]
+		if !ok {
+			d.add(true, "operation-removed", "", "删除了接口")
+			continue
+		}
+		d.opName = method + " " + newOp.path
+		d.diffOperation(oldOp, newOp)
+	}
+	for _, key := range newKeys {
+		if _, ok := oldOps[key]; ok {
+			continue
+		}
+		method, _, _ := strings.Cut(key, " ")
+		d.opName = method + " " + newOps[key].path
+		d.add(false, "operation-added", "", "新增接口")
+	}
+	d.opName = ""
+}
+
+func (d *differ) diffOperation(oldOp, newOp diffOperation) {
+	oldDeprecated, _ := oldOp.op["deprecated"].(bool)
+	newDeprecated, _ := newOp.op["deprecated"].(bool)
+	if !oldDeprecated && newDeprecated {
+		d.add(false, "operation-deprecated", "", "接口被标记为废弃")
+	}
+	d.diffParameters(oldOp, newOp)
+	d.diffRequestBody(oldOp.op, newOp.op)
+	d.diffResponses(oldOp.op, newOp.op)
+}
+
+// paramKey 参数的比较键：路径参数按在模板中的位置，其余按 in + name
+func paramKey(path string, param map[string]interface{}) string {
+	in, name := getString(param, "in"), getString(param, "name")
+	if in == "path" {
+		index := 0
+		for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+			if strings.HasPrefix(part, "{") {
+				if part == "{"+name+"}" {
+					return fmt.Sprintf("path#%d", index)
+				}
+				index++
+			}
+		}
+	}
+	if in == "header" {
+		name = strings.ToLower(name)
+	}
+	return in + ":" + name
+}
+
+func (d *differ) diffParameters(oldOp, newOp diffOperation) {
+	index := func(op diffOperation, doc map[string]interface{}) (map[string]map[string]interface{}, []string) {
+		params := make(map[string]map[string]interface{})
+		var keys []string
+		for _, p := range operationParameters(doc, op.path, op.op) {
+			key := paramKey(op.path, p)
+			params[key] = p
+			keys = append(keys, key)
+		}
+		return params, keys
+	}
+	oldParams, oldKeys := index(oldOp, d.old)
+	newParams, newKeys := index(newOp, d.new)
+
+	for _, key := range oldKeys {
+		oldParam := oldParams[key]
+		location := getString(oldParam, "in") + "." + getString(oldParam, "name")
+		newParam, ok := newParams[key]
+		if !ok {
+			d.add(false, "param-removed", location, "删除了参数")
+			continue
+		}
+		location = getString(newParam, "in") + "." + getString(newParam, "name")
+		oldRequired, _ := oldParam["required"].(bool)
+		newRequired, _ := newParam["required"].(bool)
+		switch {
+		case !oldRequired && newRequired:
+			d.add(true, "param-became-required", location, "参数由可选变为必填")
+		case oldRequired && !newRequired:
+			d.add(false, "param-became-optional", location, "参数由必填变为可选")
+		}
+		d.diffSchema(parameterSchema(oldParam), parameterSchema(newParam), location, true, 0)
+	}
+	for _, key := range newKeys {
+		if _, ok := oldParams[key]; ok {
+			continue
+		}
+		param := newParams[key]
+		location := getString(param, "in") + "." + getString(param, "name")
+		if required, _ := param["required"].(bool); required {
+			d.add(true, "param-required-added", location, "新增必填参数")
+		} else {
+			d.add(false, "param-added", location, "新增可选参数")
+		}
+	}
+}
+
+func (d *differ) diffRequestBody(oldOp, newOp map[string]interface{}) {
+	oldBody, newBody := operationRequestBody(d.old, oldOp), operationRequestBody(d.new, newOp)
+	newRequired, _ := newBody["required"].(bool)
+	if oldBody == nil {
+		if newBody != nil {
+			d.add(newRequired, "request-body-added", "request.body", "新增请求体")
+		}
+		return
+	}
+	if newBody == nil {
+		d.add(false, "request-body-removed", "request.body", "删除了请求体")
+		return
+	}
+	if oldRequired, _ := oldBody["required"].(bool); !oldRequired && newRequired {
+		d.add(true, "request-body-became-required", "request.body", "请求体由可选变为必填")
+	}
+
+	oldContent, _ := oldBody["content"].(map[string]interface{})
+	newContent, _ := newBody["content"].(map[string]interface{})
+	for _, mt := range contentKeys(oldContent) {
+		newMedia, ok := newContent[mt].(map[string]interface{})
+		if !ok {
+			d.add(true, "request-media-type-removed", "request.body", "不再支持请求体类型 %s", mt)
+			continue
+		}
+		oldMedia, _ := oldContent[mt].(map[string]interface{})
+		oldSchema, _ := oldMedia["schema"].(map[string]interface{})
+		newSchema, _ := newMedia["schema"].(map[string]interface{})
+		d.diffSchema(oldSchema, newSchema, "request.body", true, 0)
+	}
+	for _, mt := range contentKeys(newContent) {
+		if _, ok := oldContent[mt]; !ok {
+			d.add(false, "request-media-type-added", "request.body", "新增请求体类型 %s", mt)
+		}
+	}
+}
+
+func (d *differ) diffResponses(oldOp, newOp map[string]interface{}) {
+	oldResponses, _ := oldOp["responses"].(map[string]interface{})
+	newResponses, _ := newOp["responses"].(map[string]interface{})
+	resolve := func(doc map[string]interface{}, v interface{}) map[string]interface{} {
+		resp, _ := v.(map[string]interface{})
+		if ref := getString(resp, "$ref"); ref != "" {
+			resp = resolveRef(doc, ref)
+		}
+		return resp
+	}
+
+	for _, code := range contentKeys(oldResponses) {
+		location := "response." + code
+		newResp := resolve(d.new, newResponses[code])
+		if newResp == nil {
+			// 删除成功响应会让依赖它的客户端出错，删除错误响应通常无影响
+			d.add(strings.HasPrefix(code, "2"), "response-removed", location, "删除了 %s 响应", code)
+			continue
+		}
+		oldResp := resolve(d.old, oldResponses[code])
+		oldContent, _ := oldResp["content"].(map[string]interface{})
+		newContent, _ := newResp["content"].(map[string]interface{})
+		for _, mt := range contentKeys(oldContent) {
+			newMedia, ok := newContent[mt].(map[string]interface{})
+			if !ok {
+				d.add(true, "response-media-type-removed", location, "%s 响应不再返回 %s", code, mt)
+				continue
+			}
+			oldMedia, _ := oldContent[mt].(map[string]interface{})
+			oldSchema, _ := oldMedia["schema"].(map[string]interface{})
+			newSchema, _ := newMedia["schema"].(map[string]interface{})
+			d.diffSchema(oldSchema, newSchema, location+".body", false, 0)
+		}
+	}
+	for _, code := range contentKeys(newResponses) {
+		if _, ok := oldResponses[code]; !ok {
+			d.add(false, "response-added", "response."+code, "新增 %s 响应", code)
+		}
+	}
+}
+
+// flattenSchema 解析 $ref 并合并 allOf 的 properties 与 required
+func flattenSchema(doc, schema map[string]interface{}) map[string]interface{} {
+	schema = derefSchema(doc, schema)
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return schema
+	}
+	merged := copyMap(schema)
+	delete(merged, "allOf")
+	props := make(map[string]interface{})
+	var required []interface{}
+	parts := append([]interface{}{schema}, allOf...)
+	for i, part := range parts {
+		sub, _ := part.(map[string]interface{})
+		if i > 0 {
+			sub = flattenSchema(doc, sub)
+		}
+		if p, ok := sub["properties"].(map[string]interface{}); ok {
+			for k, v := range p {
+				props[k] = v
+			}
+		}
+		if r, ok := sub["required"].([]interface{}); ok {
+			required = append(required, r...)
+		}
+		if t := schemaType(sub); t != "" && merged["type"] == nil {
+			merged["type"] = t
+		}
+	}
+	if len(props) > 0 {
+		merged["properties"] = props
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return merged
+}
+
+// diffSchema 对比 schema；request 为 true 时按请求方向判断（约束收紧为破坏性），否则按响应方向判断
+func (d *differ) diffSchema(oldSchema, newSchema map[string]interface{}, location string, request bool, depth int) {
+	if oldSchema == nil || newSchema == nil || depth > maxSchemaDepth {
+		return
+	}
+	oldRef, newRef := getString(oldSchema, "$ref"), getString(newSchema, "$ref")
+	if oldRef != "" || newRef != "" {
+		pair := oldRef + "\x00" + newRef
+		if containsString(d.refs, pair) {
+			return
+		}
+		d.refs = append(d.refs, pair)
+		defer func() { d.refs = d.refs[:len(d.refs)-1] }()
+	}
+	oldS, newS := flattenSchema(d.old, oldSchema), flattenSchema(d.new, newSchema)
+	if oldS == nil || newS == nil {
+		return
+	}
+
+	oldType, newType := schemaType(oldS), schemaType(newS)
+	if oldType != "" && newType != "" && oldType != newType {
+		// 整数放宽为数字在请求方向上是兼容的
+		compatible := request && oldType == "integer" && newType == "number"
+		d.add(!compatible, "type-changed", location, "类型由 %s 变为 %s", oldType, newType)
+		return
+	}
+
+	d.diffEnum(oldS, newS, location, request)
+	d.diffConstraints(oldS, newS, location, request)
+
+	switch newType {
+	case "array":
+		oldItems, _ := oldS["items"].(map[string]interface{})
+		newItems, _ := newS["items"].(map[string]interface{})
+		d.diffSchema(oldItems, newItems, location+"[]", request, depth+1)
+	case "object", "":
+		d.diffProperties(oldS, newS, location, request, depth)
+	}
+}
+
+func (d *differ) diffProperties(oldS, newS map[string]interface{}, location string, request bool, depth int) {
+	oldProps, _ := oldS["properties"].(map[string]interface{})
+	newProps, _ := newS["properties"].(map[string]interface{})
+	oldRequired, newRequired := requiredSet(oldS), requiredSet(newS)
+
+	for _, name := range contentKeys(oldProps) {
+		field := joinField(location, name)
+		newProp, ok := newProps[name].(map[string]interface{})
+		if !ok {
+			// 响应中删除字段会影响读取它的客户端；请求中删除字段通常会被服务端忽略
+			d.add(!request, "property-removed", field, "删除了字段")
+			continue
+		}
+		switch {
+		case request && !oldRequired[name] && newRequired[name]:
+			d.add(true, "property-became-required", field, "字段由可选变为必填")
+		case !request && oldRequired[name] && !newRequired[name]:
+			d.add(true, "property-became-optional", field, "响应字段不再保证返回")
+		}
+		oldProp, _ := oldProps[name].(map[string]interface{})
+		d.diffSchema(oldProp, newProp, field, request, depth+1)
+	}
+	for _, name := range contentKeys(newProps) {
+		if _, ok := oldProps[name]; ok {
+			continue
+		}
+		field := joinField(location, name)
+		if request && newRequired[name] {
+			d.add(true, "property-required-added", field, "新增必填字段")
+		} else {
+			d.add(false, "property-added", field, "新增字段")
+		}
+	}
+}
+
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	set := make(map[string]bool)
+	for _, name := range getStringArray(schema, "required") {
+		set[name] = true
+	}
+	return set
+}
+
+// diffEnum 请求方向删除枚举值、响应方向新增枚举值为破坏性变更
+func (d *differ) diffEnum(oldS, newS map[string]interface{}, location string, request bool) {
+	oldEnum, oldOk := oldS["enum"].([]interface{})
+	newEnum, newOk := newS["enum"].([]interface{})
+	if !oldOk && !newOk {
+		return
+	}
+	if !oldOk {
+		d.add(request, "enum-added", location, "新增取值限制 %v", newEnum)
+		return
+	}
+	if !newOk {
+		d.add(!request, "enum-removed", location, "取消取值限制")
+		return
+	}
+	contains := func(list []interface{}, v interface{}) bool {
+		for _, item := range list {
+			if fmt.Sprint(item) == fmt.Sprint(v) {
+				return true
+			}
+		}
+		return false
+	}
+	var removed, added []interface{}
+	for _, v := range oldEnum {
+		if !contains(newEnum, v) {
+			removed = append(removed, v)
+		}
+	}
+	for _, v := range newEnum {
+		if !contains(oldEnum, v) {
+			added = append(added, v)
+		}
+	}
+	if len(removed) > 0 {
+		d.add(request, "enum-narrowed", location, "删除了枚举值 %v", removed)
+	}
+	if len(added) > 0 {
+		d.add(!request, "enum-widened", location, "新增了枚举值 %v", added)
+	}
+}
+
+// diffConstraints 对比长度、取值范围与元素个数约束：请求方向收紧为破坏性变更
+func (d *differ) diffConstraints(oldS, newS map[string]interface{}, location string, request bool) {
+	type bound struct {
+		key   string
+		lower bool // 下限：数值变大为收紧
+	}
+	bounds := []bound{
+		{"minLength", true}, {"maxLength", false},
+		{"minimum", true}, {"maximum", false},
+		{"minItems", true}, {"maxItems", false},
+	}
+	names := make([]string, 0)
+	for _, b := range bounds {
+		oldV, oldOk := oldS[b.key].(float64)
+		newV, newOk := newS[b.key].(float64)
+		var tightened bool
+		switch {
+		case !oldOk && newOk:
+			tightened = true
+		case oldOk && newOk && oldV != newV:
+			tightened = (b.lower && newV > oldV) || (!b.lower && newV < oldV)
+		default:
+			continue
+		}
+		if tightened && request {
+			names = append(names, fmt.Sprintf("%s=%v", b.key, newV))
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		d.add(true, "constraint-tightened", location, "约束收紧：%s", strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,198 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// diffDoc 生成只有 GET /orders 200 响应的文档，body 为响应 schema，schemas 为组件
+func diffDoc(t *testing.T, body interface{}, schemas map[string]interface{}) []byte {
+	t.Helper()
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]interface{}{"title": "diff", "version": "1.0.0"},
+		"paths": map[string]interface{}{
+			"/orders": map[string]interface{}{"get": map[string]interface{}{
+				"responses": map[string]interface{}{"200": map[string]interface{}{
+					"description": "ok",
+					"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": body}},
+				}},
+			}},
+		},
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func object(props map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func stringSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "string"}
+}
+
+// changeIndex 按 "类型 位置" 索引变更，值为是否破坏性
+func changeIndex(diff *SpecDiff) map[string]bool {
+	index := make(map[string]bool)
+	for _, c := range diff.Changes {
+		index[c.Type+" "+c.Location] = c.Breaking
+	}
+	return index
+}
+
+func TestDiffSpecsOperations(t *testing.T) {
+	oldSpec := `{
+  "openapi": "3.0.3",
+  "info": {"title": "shop", "version": "1.0.0"},
+  "paths": {
+    "/users/{id}": {
+      "get": {"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"type": "object", "required": ["email"], "properties": {"email": {"type": "string"}, "age": {"type": "integer"}}}}}}}},
+      "delete": {"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}], "responses": {"204": {"description": "ok"}}}
+    },
+    "/users": {
+      "get": {"parameters": [{"name": "sort", "in": "query", "schema": {"type": "string", "enum": ["asc", "desc"]}}],
+        "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`
+	newSpec := `{
+  "swagger": "2.0",
+  "info": {"title": "shop", "version": "2.0.0"},
+  "paths": {
+    "/users/{userId}": {
+      "get": {"parameters": [{"name": "userId", "in": "path", "required": true, "type": "string"}],
+        "produces": ["application/json"],
+        "responses": {"200": {"description": "ok", "schema": {"type": "object", "properties": {"age": {"type": "string"}, "nickname": {"type": "string"}}}}}}
+    },
+    "/users": {
+      "get": {"parameters": [
+          {"name": "sort", "in": "query", "type": "string", "enum": ["asc"]},
+          {"name": "status", "in": "query", "required": true, "type": "string"}
+        ],
+        "responses": {"200": {"description": "ok"}}}
+    },
+    "/orders": {"post": {"responses": {"201": {"description": "ok"}}}}
+  }
+}`
+	diff, err := DiffSpecs([]byte(oldSpec), []byte(newSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{
+		"operation-removed ":                        true,
+		"operation-added ":                          false,
+		"property-removed response.200.body.email":  true,
+		"type-changed response.200.body.age":        true,
+		"property-added response.200.body.nickname": false,
+		"enum-narrowed query.sort":                  true,
+		"param-required-added query.status":         true,
+	}
+	got := changeIndex(diff)
+	for key, breaking := range want {
+		if b, ok := got[key]; !ok || b != breaking {
+			t.Errorf("%s：期望 breaking=%v，实际 %v（存在 %v）", key, breaking, b, ok)
+		}
+	}
+	if len(diff.Changes) != len(want) {
+		t.Errorf("变更数 = %d：%+v", len(diff.Changes), diff.Changes)
+	}
+	if !diff.HasBreaking() || diff.OldVersion != "1.0.0" || diff.NewVersion != "2.0.0" {
+		t.Errorf("diff = %+v", diff)
+	}
+	if md := diff.Markdown(); !strings.Contains(md, "1.0.0 → 2.0.0") || !strings.Contains(md, "`DELETE /users/{id}` 删除了接口") {
+		t.Errorf("Markdown:\n%s", md)
+	}
+}
+
+func TestDiffSpecsNoChanges(t *testing.T) {
+	schemas := map[string]interface{}{"Order": object(map[string]interface{}{"id": stringSchema()})}
+	spec := diffDoc(t, ref("Order"), schemas)
+	diff, err := DiffSpecs(spec, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changes) != 0 || diff.HasBreaking() {
+		t.Errorf("相同文档的变更 = %+v", diff.Changes)
+	}
+	if _, err := DiffSpecs([]byte("{"), spec); err == nil {
+		t.Error("无效文档应返回错误")
+	}
+}
+
+// 同一组件出现在多个位置时，每个位置都报告变更
+func TestDiffSharedSchema(t *testing.T) {
+	order := object(map[string]interface{}{"billing": ref("Address"), "shipping": ref("Address")})
+	oldDoc := diffDoc(t, ref("Order"), map[string]interface{}{
+		"Order":   order,
+		"Address": object(map[string]interface{}{"city": stringSchema(), "zip": stringSchema()}),
+	})
+	newDoc := diffDoc(t, ref("Order"), map[string]interface{}{
+		"Order":   order,
+		"Address": object(map[string]interface{}{"city": stringSchema()}),
+	})
+	diff, err := DiffSpecs(oldDoc, newDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := changeIndex(diff)
+	for _, location := range []string{"response.200.body.billing.zip", "response.200.body.shipping.zip"} {
+		if !got["property-removed "+location] {
+			t.Errorf("缺少 %s 的变更：%+v", location, diff.Changes)
+		}
+	}
+	if len(diff.Changes) != 2 {
+		t.Errorf("变更数 = %d", len(diff.Changes))
+	}
+}
+
+// 组件先在接近 maxSchemaDepth 的位置出现时，较浅位置的嵌套变更仍然报告
+func TestDiffSharedSchemaNearMaxDepth(t *testing.T) {
+	build := func(address map[string]interface{}) []byte {
+		var deep interface{} = ref("Address")
+		for i := 1; i < maxSchemaDepth; i++ {
+			deep = object(map[string]interface{}{"a": deep})
+		}
+		body := object(map[string]interface{}{"a": deep, "z": ref("Address")})
+		return diffDoc(t, body, map[string]interface{}{"Address": address})
+	}
+	oldDoc := build(object(map[string]interface{}{"zip": stringSchema()}))
+	newDoc := build(object(map[string]interface{}{"zip": map[string]interface{}{"type": "integer"}}))
+	diff, err := DiffSpecs(oldDoc, newDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changeIndex(diff)["type-changed response.200.body.z.zip"] {
+		t.Errorf("缺少较浅位置的变更：%+v", diff.Changes)
+	}
+}
+
+// 递归 schema 沿当前引用链检测循环，每个位置只展开一次
+func TestDiffRecursiveSchema(t *testing.T) {
+	node := func(nameType string) map[string]interface{} {
+		return object(map[string]interface{}{
+			"name":     map[string]interface{}{"type": nameType},
+			"parent":   ref("Node"),
+			"children": map[string]interface{}{"type": "array", "items": ref("Node")},
+		})
+	}
+	oldDoc := diffDoc(t, ref("Node"), map[string]interface{}{"Node": node("string")})
+	newDoc := diffDoc(t, ref("Node"), map[string]interface{}{"Node": node("integer")})
+	diff, err := DiffSpecs(oldDoc, newDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Location != "response.200.body.name" {
+		t.Errorf("变更 = %+v", diff.Changes)
+	}
+}
@@ -0,0 +1,306 @@
+//go:build ignore
+
+package main
+
+// OAuth2 认证示例：在同一进程中运行一个本地模拟的授权服务器（:9000）与受其保护的接口（:8080），
+// 用于在文档的“认证”对话框中体验授权码（PKCE）、隐式、密码与客户端凭据模式，以及令牌自动刷新。
+//
+//	go run main_oauth2.go
+//	打开 http://localhost:8080/doc/，点击“认证”
+//
+// 模拟授权服务器自动同意授权请求；客户端 ID 为 demo-client，客户端凭据模式的密钥为 demo-secret，
+// 密码模式的账号为 demo / demo。令牌有效期只有 60 秒，便于观察自动刷新
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	qingfeng "github.com/buyfakett/qingfeng"
+)
+
+const (
+	clientID     = "demo-client"
+	clientSecret = "demo-secret"
+	tokenTTL     = 60 * time.Second
+)
+
+var spec = []byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "OAuth2 示例 API", "version": "1.0.0"},
+  "servers": [{"url": "http://localhost:8080"}],
+  "components": {
+    "securitySchemes": {
+      "demoOAuth": {
+        "type": "oauth2",
+        "description": "本地模拟授权服务器（http://localhost:9000）",
+        "flows": {
+          "authorizationCode": {
+            "authorizationUrl": "http://localhost:9000/authorize",
+            "tokenUrl": "http://localhost:9000/token",
+            "scopes": {"profile:read": "读取个人信息", "orders:write": "创建订单"}
+          },
+          "implicit": {
+            "authorizationUrl": "http://localhost:9000/authorize",
+            "scopes": {"profile:read": "读取个人信息"}
+          },
+          "password": {
+            "tokenUrl": "http://localhost:9000/token",
+            "scopes": {"profile:read": "读取个人信息", "orders:write": "创建订单"}
+          },
+          "clientCredentials": {
+            "tokenUrl": "http://localhost:9000/token",
+            "scopes": {"orders:write": "创建订单"}
+          }
+        }
+      }
+    }
+  },
+  "paths": {
+    "/api/me": {
+      "get": {
+        "summary": "当前令牌信息",
+        "operationId": "getMe",
+        "tags": ["OAuth2"],
+        "security": [{"demoOAuth": ["profile:read"]}],
+        "responses": {"200": {"description": "令牌对应的用户与权限"}, "401": {"description": "令牌无效或已过期"}}
+      }
+    }
+  }
+}`)
+
+// grant 授权码或令牌对应的授权信息
+type grant struct {
+	subject     string
+	scope       string
+	redirectURI string
+	challenge   string
+	method      string
+	expiresAt   time.Time
+}
+
+type provider struct {
+	mu            sync.Mutex
+	codes         map[string]grant
+	accessTokens  map[string]grant
+	refreshTokens map[string]grant
+}
+
+func main() {
+	p := &provider{
+		codes:         map[string]grant{},
+		accessTokens:  map[string]grant{},
+		refreshTokens: map[string]grant{},
+	}
+
+	// 模拟授权服务器
+	auth := http.NewServeMux()
+	auth.HandleFunc("/authorize", p.handleAuthorize)
+	auth.HandleFunc("/token", p.handleToken)
+	go func() {
+		log.Println("OAuth2 provider starting on :9000")
+		log.Fatal(http.ListenAndServe(":9000", auth))
+	}()
+
+	// 文档与受保护的接口
+	mux := http.NewServeMux()
+	mux.Handle("/doc/", qingfeng.HTTPHandler(qingfeng.Config{
+		Title:       "OAuth2 示例",
+		BasePath:    "/doc",
+		DocJSON:     spec,
+		EnableDebug: true,
+		OAuth2: &qingfeng.OAuth2Config{
+			ClientID: clientID,
+		},
+	}))
+	mux.HandleFunc("/api/me", p.handleMe)
+
+	log.Println("Server starting on :8080, open http://localhost:8080/doc/")
+	log.Fatal(http.ListenAndServe(":8080", mux))
+}
+
+// handleAuthorize 自动同意授权：授权码模式重定向回 ?code=，隐式模式重定向回 #access_token=
+func (p *provider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if q.Get("client_id") != clientID || err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid client_id or redirect_uri", http.StatusBadRequest)
+		return
+	}
+	g := grant{subject: "demo", scope: q.Get("scope"), redirectURI: q.Get("redirect_uri")}
+
+	params := url.Values{"state": {q.Get("state")}}
+	switch q.Get("response_type") {
+	case "code":
+		g.challenge, g.method = q.Get("code_challenge"), q.Get("code_challenge_method")
+		if g.challenge == "" {
+			params.Set("error", "invalid_request")
+			params.Set("error_description", "PKCE code_challenge is required")
+			break
+		}
+		g.expiresAt = time.Now().Add(time.Minute)
+		code := randomString()
+		p.mu.Lock()
+		p.codes[code] = g
+		p.mu.Unlock()
+		params.Set("code", code)
+	case "token":
+		for k, v := range p.issue(g, false) {
+			params.Set(k, fmt.Sprint(v))
+		}
+		// 隐式模式的令牌放在 # 片段中
+		redirectURI.Fragment = ""
+		http.Redirect(w, r, redirectURI.String()+"#"+params.Encode(), http.StatusFound)
+		return
+	default:
+		params.Set("error", "unsupported_response_type")
+	}
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// handleToken 令牌端点，支持 authorization_code、password、client_credentials 与 refresh_token
+func (p *provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	// 文档页面在浏览器中直接请求令牌端点，需要允许跨域
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		tokenError(w, http.StatusMethodNotAllowed, "invalid_request", "POST required")
+		return
+	}
+	r.ParseForm()
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != clientID || (secret != "" && secret != clientSecret) {
+		tokenError(w, http.StatusUnauthorized, "invalid_client", "unknown client or wrong secret")
+		return
+	}
+
+	var g grant
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		p.mu.Lock()
+		code, found := p.codes[r.PostForm.Get("code")]
+		delete(p.codes, r.PostForm.Get("code"))
+		p.mu.Unlock()
+		if !found || time.Now().After(code.expiresAt) || code.redirectURI != r.PostForm.Get("redirect_uri") {
+			tokenError(w, http.StatusBadRequest, "invalid_grant", "invalid code or redirect_uri")
+			return
+		}
+		if !verifyPKCE(code, r.PostForm.Get("code_verifier")) {
+			tokenError(w, http.StatusBadRequest, "invalid_grant", "PKCE verification failed")
+			return
+		}
+		g = code
+	case "password":
+		if r.PostForm.Get("username") != "demo" || r.PostForm.Get("password") != "demo" {
+			tokenError(w, http.StatusBadRequest, "invalid_grant", "wrong username or password")
+			return
+		}
+		g = grant{subject: "demo", scope: r.PostForm.Get("scope")}
+	case "client_credentials":
+		if secret != clientSecret {
+			tokenError(w, http.StatusUnauthorized, "invalid_client", "client_secret is required")
+			return
+		}
+		g = grant{subject: clientID, scope: r.PostForm.Get("scope")}
+	case "refresh_token":
+		// 刷新令牌只能使用一次，每次刷新返回新的刷新令牌
+		p.mu.Lock()
+		refresh, found := p.refreshTokens[r.PostForm.Get("refresh_token")]
+		delete(p.refreshTokens, r.PostForm.Get("refresh_token"))
+		p.mu.Unlock()
+		if !found {
+			tokenError(w, http.StatusBadRequest, "invalid_grant", "unknown refresh_token")
+			return
+		}
+		g = refresh
+	default:
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type", r.PostForm.Get("grant_type"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	// 客户端凭据模式不返回刷新令牌
+	json.NewEncoder(w).Encode(p.issue(g, r.PostForm.Get("grant_type") != "client_credentials"))
+}
+
+// issue 签发访问令牌（以及刷新令牌）
+func (p *provider) issue(g grant, withRefresh bool) map[string]interface{} {
+	access := randomString()
+	token := map[string]interface{}{
+		"access_token": access,
+		"token_type":   "Bearer",
+		"expires_in":   int(tokenTTL.Seconds()),
+		"scope":        g.scope,
+	}
+	g.expiresAt = time.Now().Add(tokenTTL)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.accessTokens[access] = g
+	if withRefresh {
+		refresh := randomString()
+		p.refreshTokens[refresh] = g
+		token["refresh_token"] = refresh
+	}
+	log.Printf("issued token for %s (scope %q)", g.subject, g.scope)
+	return token
+}
+
+// handleMe 受保护的接口，返回令牌对应的用户与权限
+func (p *provider) handleMe(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	p.mu.Lock()
+	g, found := p.accessTokens[token]
+	p.mu.Unlock()
+	if !found || time.Now().After(g.expiresAt) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_token"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sub":        g.subject,
+		"scope":      g.scope,
+		"expires_at": g.expiresAt.Format(time.RFC3339),
+	})
+}
+
+func verifyPKCE(g grant, verifier string) bool {
+	if verifier == "" {
+		return false
+	}
+	if g.method == "S256" {
+		sum := sha256.Sum256([]byte(verifier))
+		return base64.RawURLEncoding.EncodeToString(sum[:]) == g.challenge
+	}
+	return verifier == g.challenge
+}
+
+func tokenError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code, "error_description": description})
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
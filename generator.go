@@ -197,6 +197,9 @@ func convertSwagger2ToOpenAPI3(data []byte) ([]byte, error) {
 			})
 		}
 		openapi3["servers"] = servers
+	} else if basePath != "" {
+		// 没有 host 时使用相对地址，保留 basePath 前缀
+		openapi3["servers"] = []map[string]interface{}{{"url": basePath}}
 	}
 
 	// 复制 paths（需要转换 body 参数为 requestBody）
@@ -211,6 +214,19 @@ func convertSwagger2ToOpenAPI3(data []byte) ([]byte, error) {
 		}
 	}
 
+	// 转换 securityDefinitions 为 components/securitySchemes，并复制全局 security
+	if definitions, ok := swagger2["securityDefinitions"].(map[string]interface{}); ok {
+		components, ok := openapi3["components"].(map[string]interface{})
+		if !ok {
+			components = make(map[string]interface{})
+			openapi3["components"] = components
+		}
+		components["securitySchemes"] = convertSecurityDefinitions(definitions)
+	}
+	if security, ok := swagger2["security"]; ok {
+		openapi3["security"] = security
+	}
+
 	// 复制 tags
 	if tags, ok := swagger2["tags"]; ok {
 		openapi3["tags"] = tags
@@ -219,6 +235,51 @@ func convertSwagger2ToOpenAPI3(data []byte) ([]byte, error) {
 	return json.Marshal(openapi3)
 }
 
+// convertSecurityDefinitions 将 Swagger 2.0 的 basic/apiKey/oauth2 定义转换为 OpenAPI 3 securitySchemes
+func convertSecurityDefinitions(definitions map[string]interface{}) map[string]interface{} {
+	schemes := make(map[string]interface{})
+	for name, def := range definitions {
+		d, ok := def.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		scheme := make(map[string]interface{})
+		if desc := getString(d, "description"); desc != "" {
+			scheme["description"] = desc
+		}
+		switch getString(d, "type") {
+		case "basic":
+			scheme["type"] = "http"
+			scheme["scheme"] = "basic"
+		case "apiKey":
+			scheme["type"] = "apiKey"
+			scheme["name"] = d["name"]
+			scheme["in"] = d["in"]
+		case "oauth2":
+			flow := map[string]interface{}{"scopes": map[string]interface{}{}}
+			if scopes, ok := d["scopes"].(map[string]interface{}); ok {
+				flow["scopes"] = scopes
+			}
+			for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+				if v := getString(d, key); v != "" {
+					flow[key] = v
+				}
+			}
+			flowNames := map[string]string{"implicit": "implicit", "password": "password", "application": "clientCredentials", "accessCode": "authorizationCode"}
+			flowName, ok := flowNames[getString(d, "flow")]
+			if !ok {
+				flowName = getString(d, "flow")
+			}
+			scheme["type"] = "oauth2"
+			scheme["flows"] = map[string]interface{}{flowName: flow}
+		default:
+			scheme = d
+		}
+		schemes[name] = scheme
+	}
+	return schemes
+}
+
 // convertPaths 转换 paths 中的参数格式
 func convertPaths(paths map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
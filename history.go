@@ -0,0 +1,312 @@
+package qingfeng
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpecVersion is one entry in the document history
+// 文档历史中的一个版本
+type SpecVersion struct {
+	// Version 文档 info.version
+	Version string `json:"version"`
+	// Hash 文档内容哈希（规范化 JSON 的 SHA-256 前 12 位），同一 info.version 的不同内容以此区分
+	Hash string    `json:"hash"`
+	Time time.Time `json:"time"`
+}
+
+// HistoryStore persists document versions, oldest first
+// 文档历史存储，可替换为数据库、对象存储等实现
+type HistoryStore interface {
+	// List 按记录时间从旧到新返回所有版本
+	List() ([]SpecVersion, error)
+	// Load 读取指定哈希的文档内容
+	Load(hash string) ([]byte, error)
+	// Save 追加一个版本
+	Save(v SpecVersion, spec []byte) error
+	// Delete 删除指定哈希的版本
+	Delete(hash string) error
+}
+
+// FileHistoryStore stores versions as <hash>.json files plus an index.json in Dir
+// 基于文件的历史存储：Dir 下保存 index.json 与每个版本的 <hash>.json
+type FileHistoryStore struct {
+	Dir string
+	mu  sync.Mutex
+}
+
+// NewFileHistoryStore 创建文件历史存储，dir 为空时使用 ./docs/history
+func NewFileHistoryStore(dir string) *FileHistoryStore {
+	if dir == "" {
+		dir = "./docs/history"
+	}
+	return &FileHistoryStore{Dir: dir}
+}
+
+func (s *FileHistoryStore) indexPath() string {
+	return filepath.Join(s.Dir, "index.json")
+}
+
+func (s *FileHistoryStore) readIndex() ([]SpecVersion, error) {
+	data, err := os.ReadFile(s.indexPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []SpecVersion
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("解析文档历史索引失败: %w", err)
+	}
+	return versions, nil
+}
+
+func (s *FileHistoryStore) writeIndex(versions []SpecVersion) error {
+	data, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.indexPath(), data)
+}
+
+// List 实现 HistoryStore
+func (s *FileHistoryStore) List() ([]SpecVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readIndex()
+}
+
+// Load 实现 HistoryStore
+func (s *FileHistoryStore) Load(hash string) ([]byte, error) {
+	if strings.ContainsAny(hash, `/\.`) {
+		return nil, fmt.Errorf("无效的版本哈希: %s", hash)
+	}
+	return os.ReadFile(filepath.Join(s.Dir, hash+".json"))
+}
+
+// Save 实现 HistoryStore
+func (s *FileHistoryStore) Save(v SpecVersion, spec []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := writeFileAtomic(filepath.Join(s.Dir, v.Hash+".json"), spec); err != nil {
+		return err
+	}
+	versions, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+	return s.writeIndex(append(versions, v))
+}
+
+// Delete 实现 HistoryStore
+func (s *FileHistoryStore) Delete(hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+	kept := versions[:0]
+	for _, v := range versions {
+		if v.Hash != hash {
+			kept = append(kept, v)
+		}
+	}
+	if err := s.writeIndex(kept); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.Dir, hash+".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// HistoryConfig configures a SpecHistory
+// 文档历史配置
+type HistoryConfig struct {
+	// Store 历史存储，默认 NewFileHistoryStore("./docs/history")
+	Store HistoryStore
+	// MaxVersions 最多保留的版本数，超出时删除最旧的版本（默认 20）
+	MaxVersions int
+}
+
+// SpecHistory keeps a bounded history of generated documents for the /changelog view
+// 文档历史：每次生成新文档时记录一个版本，用于 /changelog 对比任意两个版本
+type SpecHistory struct {
+	cfg HistoryConfig
+	mu  sync.Mutex
+}
+
+// NewSpecHistory 创建文档历史
+func NewSpecHistory(cfg ...HistoryConfig) *SpecHistory {
+	var c HistoryConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Store == nil {
+		c.Store = NewFileHistoryStore("")
+	}
+	if c.MaxVersions <= 0 {
+		c.MaxVersions = 20
+	}
+	return &SpecHistory{cfg: c}
+}
+
+// specHash 计算文档内容哈希，JSON 格式化差异（缩进、键顺序）不影响结果
+func specHash(spec []byte) (string, error) {
+	var doc interface{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return "", fmt.Errorf("解析文档失败: %w", err)
+	}
+	canonical, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// Record stores spec as the newest version unless it equals the latest one
+// 记录一个新版本：内容与最新版本相同时不重复记录；内容与更早的版本相同时将其移到最新
+// 文档热更新后可直接调用 Record 记录新版本
+func (h *SpecHistory) Record(spec []byte) (SpecVersion, error) {
+	hash, err := specHash(spec)
+	if err != nil {
+		return SpecVersion{}, err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	versions, err := h.cfg.Store.List()
+	if err != nil {
+		return SpecVersion{}, err
+	}
+	if n := len(versions); n > 0 && versions[n-1].Hash == hash {
+		return versions[n-1], nil
+	}
+	for _, v := range versions {
+		if v.Hash == hash {
+			if err := h.cfg.Store.Delete(hash); err != nil {
+				return SpecVersion{}, err
+			}
+		}
+	}
+
+	info, _ := parseSpec(spec)["info"].(map[string]interface{})
+	v := SpecVersion{Version: getString(info, "version"), Hash: hash, Time: time.Now()}
+	if err := h.cfg.Store.Save(v, spec); err != nil {
+		return SpecVersion{}, err
+	}
+
+	if versions, err = h.cfg.Store.List(); err != nil {
+		return v, err
+	}
+	for i := 0; i < len(versions)-h.cfg.MaxVersions; i++ {
+		if err := h.cfg.Store.Delete(versions[i].Hash); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+// Versions 按记录时间从旧到新返回所有版本
+func (h *SpecHistory) Versions() ([]SpecVersion, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.cfg.Store.List()
+}
+
+// Changelog is the difference between two recorded versions
+// 两个历史版本之间的变更
+type Changelog struct {
+	From SpecVersion `json:"from"`
+	To   SpecVersion `json:"to"`
+	Diff *SpecDiff   `json:"diff"`
+	// Operations 新版本中各接口的状态：new（新增）或 changed（有变更），键为 "GET /users/{id}"
+	Operations map[string]string `json:"operations"`
+}
+
+// minHashPrefix 按哈希前缀查找版本时前缀的最短长度，避免 "2" 这类版本号被当作哈希前缀
+const minHashPrefix = 7
+
+// findVersion 按 info.version、完整哈希或不短于 minHashPrefix 的哈希前缀依次查找版本，
+// 同一 version 有多个时取最新的
+func findVersion(versions []SpecVersion, ref string) (int, bool) {
+	matches := []func(v SpecVersion) bool{
+		func(v SpecVersion) bool { return v.Version == ref },
+		func(v SpecVersion) bool { return v.Hash == ref },
+		func(v SpecVersion) bool { return len(ref) >= minHashPrefix && strings.HasPrefix(v.Hash, ref) },
+	}
+	for _, match := range matches {
+		for i := len(versions) - 1; i >= 0; i-- {
+			if match(versions[i]) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Changelog compares two versions identified by hash or info.version
+// 对比两个版本：from、to 可以是 info.version 或哈希（前缀至少 7 位），两者冲突时以 info.version 为准；
+// to 为空时使用最新版本，from 为空时使用 to 的上一个版本
+func (h *SpecHistory) Changelog(from, to string) (*Changelog, error) {
+	versions, err := h.Versions()
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("暂无文档历史")
+	}
+
+	toIndex := len(versions) - 1
+	if to != "" {
+		var ok bool
+		if toIndex, ok = findVersion(versions, to); !ok {
+			return nil, fmt.Errorf("未找到版本: %s", to)
+		}
+	}
+	fromIndex := toIndex - 1
+	if from != "" {
+		var ok bool
+		if fromIndex, ok = findVersion(versions, from); !ok {
+			return nil, fmt.Errorf("未找到版本: %s", from)
+		}
+	}
+	if fromIndex < 0 {
+		return nil, fmt.Errorf("历史版本不足，无法对比")
+	}
+
+	oldSpec, err := h.cfg.Store.Load(versions[fromIndex].Hash)
+	if err != nil {
+		return nil, err
+	}
+	newSpec, err := h.cfg.Store.Load(versions[toIndex].Hash)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffSpecs(oldSpec, newSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make(map[string]string)
+	for _, c := range diff.Changes {
+		switch {
+		case c.Operation == "" || c.Type == "operation-removed":
+		case c.Type == "operation-added":
+			operations[c.Operation] = "new"
+		case operations[c.Operation] == "":
+			operations[c.Operation] = "changed"
+		}
+	}
+	return &Changelog{From: versions[fromIndex], To: versions[toIndex], Diff: diff, Operations: operations}, nil
+}
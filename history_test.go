@@ -0,0 +1,30 @@
+package qingfeng
+
+import "testing"
+
+func TestFindVersion(t *testing.T) {
+	versions := []SpecVersion{
+		{Version: "1", Hash: "2a9f0c41d7e3"},
+		{Version: "2", Hash: "20240101ab12"},
+		{Version: "20240101", Hash: "9b1e77c0aa55"},
+		{Version: "2", Hash: "c3d4e5f60718"},
+	}
+	cases := []struct {
+		ref   string
+		index int
+		found bool
+	}{
+		{"2", 3, true},            // version 优先于哈希前缀，同一 version 取最新
+		{"20240101", 2, true},     // 不会匹配到以 20240101 开头的哈希
+		{"2a9f0c41d7e3", 0, true}, // 完整哈希
+		{"20240101a", 1, true},    // 足够长的哈希前缀
+		{"2a9f0c", 0, false},      // 前缀太短
+		{"3", 0, false},
+	}
+	for _, c := range cases {
+		index, found := findVersion(versions, c.ref)
+		if found != c.found || (found && index != c.index) {
+			t.Errorf("findVersion(%q) = %d, %v", c.ref, index, found)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultLanguage 内置文案的语言，其他语言包缺少的文案使用该语言
+const defaultLanguage = "zh-CN"
+
+// builtinLanguages 内置语言包，顺序即 config.json 中 languages 的顺序
+var builtinLanguages = []string{"zh-CN", "en"}
+
+// languageInfo config.json 中的可选语言
+type languageInfo struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// locales 界面语言包：内置语言包与 Config.Locales 合并后的结果
+type locales struct {
+	// languages 按顺序排列的可选语言
+	languages []languageInfo
+	codes     []string
+	// bundles 语言代码 → 语言包 JSON（已补全缺少的文案）
+	bundles map[string][]byte
+}
+
+// loadLocales 加载内置语言包并合并自定义语言包：与内置语言同名时覆盖其中的文案，
+// 新语言按名称排在内置语言之后
+func loadLocales(custom map[string]map[string]string) *locales {
+	messages := make(map[string]map[string]string)
+	names := append([]string(nil), builtinLanguages...)
+	for _, lang := range builtinLanguages {
+		data, err := fs.ReadFile(uiFS, path.Join("ui/i18n", lang+".json"))
+		if err != nil {
+			continue
+		}
+		var m map[string]string
+		if err := json.Unmarshal(data, &m); err != nil {
+			log.Printf("[QingFeng] 解析内置语言包 %s 失败: %v\n", lang, err)
+			continue
+		}
+		messages[lang] = m
+	}
+
+	customNames := make([]string, 0, len(custom))
+	for lang := range custom {
+		customNames = append(customNames, lang)
+	}
+	sort.Strings(customNames)
+	for _, lang := range customNames {
+		m, ok := messages[lang]
+		if !ok {
+			m = make(map[string]string)
+			messages[lang] = m
+			names = append(names, lang)
+		}
+		for key, text := range custom[lang] {
+			m[key] = text
+		}
+	}
+
+	l := &locales{bundles: make(map[string][]byte, len(names))}
+	for _, lang := range names {
+		// 缺少的文案依次使用基础语言（en-GB → en）与默认语言的文案
+		bundle := make(map[string]string)
+		for _, fallback := range []string{defaultLanguage, baseLanguage(lang), lang} {
+			for key, text := range messages[fallback] {
+				bundle[key] = text
+			}
+		}
+		name := messages[lang]["language.name"]
+		if name == "" {
+			name = lang
+		}
+		l.languages = append(l.languages, languageInfo{Code: lang, Name: name})
+		l.codes = append(l.codes, lang)
+		l.bundles[lang], _ = json.Marshal(bundle)
+	}
+	return l
+}
+
+// match 返回与 tag 匹配的界面语言，没有匹配时返回空字符串
+func (l *locales) match(tag string) string {
+	return matchLanguage(tag, l.codes)
+}
+
+// negotiate 根据 Accept-Language 选择界面语言，没有匹配时返回空字符串
+func (l *locales) negotiate(header string) string {
+	return negotiateLanguage(header, l.codes)
+}
+
+// matchLanguage 返回 available 中与 tag 匹配的语言代码（不区分大小写，en-US 可匹配 en，zh 可匹配 zh-CN），
+// 没有匹配时返回空字符串
+func matchLanguage(tag string, available []string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ""
+	}
+	for _, lang := range available {
+		if strings.EqualFold(lang, tag) {
+			return lang
+		}
+	}
+	base := baseLanguage(tag)
+	for _, lang := range available {
+		if baseLanguage(lang) == base {
+			return lang
+		}
+	}
+	return ""
+}
+
+// negotiateLanguage 根据 Accept-Language 按 q 值从高到低选择 available 中的语言，没有匹配时返回空字符串
+func negotiateLanguage(header string, available []string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if tag != "" && tag != "*" && q > 0 {
+			candidates = append(candidates, candidate{tag, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	for _, c := range candidates {
+		if lang := matchLanguage(c.tag, available); lang != "" {
+			return lang
+		}
+	}
+	return ""
+}
+
+// baseLanguage 返回语言代码的主语言部分，如 en-US → en
+func baseLanguage(tag string) string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	return strings.ToLower(base)
+}
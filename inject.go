@@ -0,0 +1,135 @@
+package qingfeng
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"regexp"
+	"strings"
+)
+
+// pageInjector 将自定义 CSS、脚本与 HTML 注入主题页面
+type pageInjector struct {
+	css  string
+	js   string
+	head string
+}
+
+// newPageInjector 根据配置创建注入器，未配置任何注入内容时返回 nil
+func newPageInjector(cfg Config) *pageInjector {
+	if cfg.CustomCSS == "" && cfg.CustomJS == "" && cfg.HeadHTML == "" {
+		return nil
+	}
+	return &pageInjector{
+		// 避免内容提前结束所在的标签
+		css:  strings.ReplaceAll(cfg.CustomCSS, "</style", `<\/style`),
+		js:   strings.ReplaceAll(cfg.CustomJS, "</script", `<\/script`),
+		head: cfg.HeadHTML,
+	}
+}
+
+// render 注入自定义内容并为页面中所有 <script>、<style> 标签加上 nonce：
+// HeadHTML 与 CustomCSS 插入 </head> 之前（晚于主题样式，可覆盖 CSS 变量），CustomJS 插入 app.js 之后
+func (p *pageInjector) render(page []byte, nonce string) []byte {
+	html := string(page)
+	var head, body strings.Builder
+	head.WriteString(p.head)
+	if p.css != "" {
+		head.WriteString("\n<style>\n" + p.css + "\n</style>\n")
+	}
+	if p.js != "" {
+		body.WriteString("\n<script>\n" + p.js + "\n</script>\n")
+	}
+	html = insertBefore(html, "</head>", head.String())
+	html = insertBefore(html, "</body>", body.String())
+	return []byte(addNonceToTags(html, nonce))
+}
+
+// insertBefore 在最后一个 marker 之前插入内容，页面中没有 marker 时追加到末尾
+func insertBefore(html, marker, content string) string {
+	if content == "" {
+		return html
+	}
+	i := strings.LastIndex(html, marker)
+	if i < 0 {
+		return html + content
+	}
+	return html[:i] + content + html[i:]
+}
+
+// nonceTagPattern 匹配 <script>、<style> 开始标签
+var nonceTagPattern = regexp.MustCompile(`(?i)<(script|style)\b[^>]*>`)
+
+// addNonceToTags 为没有 nonce 属性的 <script>、<style> 标签加上 nonce
+func addNonceToTags(html, nonce string) string {
+	return nonceTagPattern.ReplaceAllStringFunc(html, func(tag string) string {
+		if strings.Contains(strings.ToLower(tag), "nonce=") {
+			return tag
+		}
+		name := nonceTagPattern.FindStringSubmatch(tag)[1]
+		return "<" + name + ` nonce="` + nonce + `"` + tag[1+len(name):]
+	})
+}
+
+// newNonce 生成 CSP nonce（每次请求不同）
+func newNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// addCSPNonce 将 nonce 加入 Content-Security-Policy 的 script-src 与 style-src；
+// 策略中没有这两个指令但有 default-src 时，以 default-src 的来源为基础新增。
+// 已允许 'unsafe-inline' 的指令保持不变（出现 nonce 时浏览器会忽略 'unsafe-inline'）
+func addCSPNonce(policy, nonce string) string {
+	source := "'nonce-" + nonce + "'"
+	directives := strings.Split(policy, ";")
+	found := map[string]bool{}
+	defaultSrc := ""
+	for i, d := range directives {
+		fields := strings.Fields(d)
+		if len(fields) == 0 {
+			continue
+		}
+		switch name := strings.ToLower(fields[0]); name {
+		case "script-src", "style-src":
+			found[name] = true
+			if !allowsUnsafeInline(fields) {
+				directives[i] = strings.TrimSpace(d) + " " + source
+			}
+		case "default-src":
+			if !allowsUnsafeInline(fields) {
+				defaultSrc = strings.Join(fields[1:], " ")
+			}
+		}
+	}
+	for _, name := range []string{"script-src", "style-src"} {
+		if !found[name] && defaultSrc != "" {
+			directives = append(directives, name+" "+defaultSrc+" "+source)
+		}
+	}
+	for i := range directives {
+		directives[i] = strings.TrimSpace(directives[i])
+	}
+	return strings.Join(nonEmpty(directives), "; ")
+}
+
+// allowsUnsafeInline 判断指令是否包含 'unsafe-inline'
+func allowsUnsafeInline(fields []string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(f, "'unsafe-inline'") {
+			return true
+		}
+	}
+	return false
+}
+
+// nonEmpty 去掉空字符串
+func nonEmpty(list []string) []string {
+	result := list[:0]
+	for _, s := range list {
+		if s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
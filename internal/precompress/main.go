@@ -0,0 +1,70 @@
+// Command precompress 为内嵌的 UI 静态文件生成 .gz 与 .br 预压缩版本
+//
+// 用法（在仓库根目录执行 go generate 即可）:
+//
+//	go run ./internal/precompress ui
+//
+// 只处理 .css 与 .js 文件；index.html 在运行时会改写资源地址，无需预压缩。
+// 运行时会校验预压缩文件与源文件是否一致，过期的文件会被忽略并在日志中提示。
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/andybalholm/brotli"
+)
+
+// minSize 与运行时一致，小于该大小的文件不压缩
+const minSize = 1024
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "用法: precompress <目录>")
+		os.Exit(2)
+	}
+	err := filepath.WalkDir(os.Args[1], func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if ext := filepath.Ext(path); ext != ".css" && ext != ".js" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || len(data) < minSize {
+			return err
+		}
+		for ext, compress := range map[string]func(io.Writer) io.WriteCloser{
+			".gz": func(w io.Writer) io.WriteCloser {
+				zw, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+				return zw
+			},
+			".br": func(w io.Writer) io.WriteCloser {
+				return brotli.NewWriterLevel(w, brotli.BestCompression)
+			},
+		} {
+			var buf bytes.Buffer
+			zw := compress(&buf)
+			if _, err := zw.Write(data); err != nil {
+				return err
+			}
+			if err := zw.Close(); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path+ext, buf.Bytes(), 0o644); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%s: %d -> gzip/brotli\n", path, len(data))
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "预压缩失败: %v\n", err)
+		os.Exit(1)
+	}
+}
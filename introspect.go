@@ -0,0 +1,206 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// anonymousHandler 匹配匿名函数处理器名称，如 "main.main.func1"
+var anonymousHandler = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+// versionSegment 匹配版本号路径段，如 "v1"、"v2.1"
+var versionSegment = regexp.MustCompile(`^v\d+(\.\d+)*$`)
+
+// IntrospectSpec builds a baseline document from registered routes and merges annotated operations on top
+// 根据注册的路由生成基础文档，并与注释生成的文档（annotated，可为 nil）合并：
+// 已有注释的接口保持不变，未注释的路由以路径参数 + 默认响应的形式补充进文档。
+//
+// Gin 的 :id、*path 以及 Chi 的 {id}、{id:[0-9]+} 都会转换为 OpenAPI 路径参数。
+// annotated 为 Swagger 2.0 时生成 Swagger 2.0 格式的操作，否则生成 OpenAPI 3 格式；
+// 文档声明了 basePath/servers 前缀时，不在该前缀下的路由会被忽略。
+func IntrospectSpec(cfg Config, routes []Route, annotated []byte) ([]byte, error) {
+	var spec map[string]interface{}
+	if len(annotated) > 0 {
+		if err := json.Unmarshal(annotated, &spec); err != nil {
+			return nil, fmt.Errorf("解析文档失败: %w", err)
+		}
+	}
+	if spec == nil {
+		spec = newBaseSpec(cfg)
+	}
+	swagger2 := getString(spec, "swagger") != ""
+
+	paths, ok := spec["paths"].(map[string]interface{})
+	if !ok {
+		paths = make(map[string]interface{})
+		spec["paths"] = paths
+	}
+	shapes := make(map[string]string) // 归一化路径 -> 文档路径
+	for p := range paths {
+		shapes[routeShape(p)] = p
+	}
+
+	basePaths := specBasePaths(spec)
+	ignore := []string{cfg.BasePath}
+	if cfg.BasePath == "" {
+		ignore = []string{"/doc"}
+	}
+	for _, route := range routes {
+		method := strings.ToLower(route.Method)
+		if !isHTTPMethod(method) || ignoredRoute(route.Path, ignore) {
+			continue
+		}
+		routePath, ok := stripBasePath(route.Path, basePaths)
+		if !ok {
+			continue
+		}
+		tmpl := routeTemplate(routePath)
+		docPath, exists := shapes[routeShape(tmpl)]
+		if !exists {
+			docPath = tmpl
+			shapes[routeShape(tmpl)] = tmpl
+			paths[docPath] = make(map[string]interface{})
+		}
+		item, ok := paths[docPath].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := item[method]; ok {
+			continue
+		}
+		item[method] = introspectOperation(docPath, method, route.Handler, swagger2)
+	}
+	return json.Marshal(spec)
+}
+
+// newBaseSpec 创建只包含基本信息的 OpenAPI 3 文档
+func newBaseSpec(cfg Config) map[string]interface{} {
+	info := map[string]interface{}{"title": cfg.Title, "description": cfg.Description, "version": cfg.Version}
+	if cfg.Title == "" {
+		info["title"] = "API Documentation"
+	}
+	if cfg.Version == "" {
+		info["version"] = "1.0.0"
+	}
+	return map[string]interface{}{"openapi": "3.0.3", "info": info}
+}
+
+// stripBasePath 去掉文档声明的路径前缀；文档声明了前缀但路由不在其下时返回 false
+func stripBasePath(routePath string, basePaths []string) (string, bool) {
+	if len(basePaths) == 0 {
+		return routePath, true
+	}
+	for _, bp := range basePaths {
+		bp = strings.TrimSuffix(bp, "/")
+		if routePath == bp {
+			return "/", true
+		}
+		if strings.HasPrefix(routePath, bp+"/") {
+			return strings.TrimPrefix(routePath, bp), true
+		}
+	}
+	return "", false
+}
+
+// routeTemplate 将框架路由写法转换为 OpenAPI 路径模板：:id → {id}、*path → {path}、{id:[0-9]+} → {id}
+func routeTemplate(routePath string) string {
+	parts := strings.Split(routePath, "/")
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			parts[i] = "{" + part[1:] + "}"
+		case strings.HasPrefix(part, "*"):
+			name := part[1:]
+			if name == "" {
+				name = "path"
+			}
+			parts[i] = "{" + name + "}"
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			name, _, _ := strings.Cut(part[1:len(part)-1], ":")
+			parts[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// introspectOperation 为未注释的路由生成基础操作：路径参数、默认请求体与 200 响应
+func introspectOperation(docPath, method, handler string, swagger2 bool) map[string]interface{} {
+	name := handlerDisplayName(handler)
+	summary := name
+	if summary == "" {
+		summary = strings.ToUpper(method) + " " + docPath
+	}
+	op := map[string]interface{}{
+		"summary":     summary,
+		"description": "该接口未添加注释，由路由自动生成",
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{"description": "OK"},
+		},
+		"x-qingfeng-introspected": true,
+	}
+	if tag := introspectTag(docPath); tag != "" {
+		op["tags"] = []string{tag}
+	}
+	if handler != "" {
+		op["x-handler"] = handler
+	}
+
+	var params []interface{}
+	for _, part := range strings.Split(docPath, "/") {
+		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+			continue
+		}
+		param := map[string]interface{}{"name": part[1 : len(part)-1], "in": "path", "required": true}
+		if swagger2 {
+			param["type"] = "string"
+		} else {
+			param["schema"] = map[string]interface{}{"type": "string"}
+		}
+		params = append(params, param)
+	}
+
+	if method == "post" || method == "put" || method == "patch" {
+		body := map[string]interface{}{"type": "object"}
+		if swagger2 {
+			params = append(params, map[string]interface{}{"name": "body", "in": "body", "schema": body})
+			op["consumes"] = []string{"application/json"}
+		} else {
+			op["requestBody"] = map[string]interface{}{
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": body},
+				},
+			}
+		}
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	return op
+}
+
+// handlerDisplayName 从处理器全名提取可读名称：
+// "github.com/x/api/handlers.(*UserHandler).Get-fm" → "UserHandler.Get"，"main.getUsers" → "getUsers"，
+// 匿名函数返回空字符串
+func handlerDisplayName(handler string) string {
+	if handler == "" || anonymousHandler.MatchString(handler) {
+		return ""
+	}
+	name := strings.TrimSuffix(path.Base(handler), "-fm")
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.NewReplacer("(*", "", "(", "", ")", "").Replace(name)
+}
+
+// introspectTag 以第一个有意义的路径段作为分组标签（跳过参数、"api" 与版本号段）
+func introspectTag(docPath string) string {
+	for _, part := range strings.Split(strings.Trim(docPath, "/"), "/") {
+		if part != "" && !strings.HasPrefix(part, "{") && part != "api" && !versionSegment.MatchString(part) {
+			return part
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package qingfeng
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// indexOperationFields 索引中保留的接口字段，足够渲染侧边栏（含认证锁图标）与搜索
+var indexOperationFields = []string{"summary", "tags", "deprecated", "operationId", "security"}
+
+// indexSkippedFields 索引中省略的顶层字段（按需加载的接口详情中已展开）
+var indexSkippedFields = map[string]bool{
+	"paths": true, "webhooks": true, "definitions": true, "parameters": true, "responses": true, "components": true,
+}
+
+// operationStore 按文档内容缓存轻量索引与单个接口的详情，文档变化时重新生成
+type operationStore struct {
+	mu    sync.Mutex
+	src   []byte
+	doc   map[string]interface{}
+	index []byte
+	ops   map[string][]byte
+}
+
+// reset 文档内容变化时清空缓存（调用方持有锁）
+func (s *operationStore) reset(spec []byte) error {
+	if s.doc != nil && bytes.Equal(s.src, spec) {
+		return nil
+	}
+	doc := parseSpec(spec)
+	if doc == nil {
+		return fmt.Errorf("解析文档失败")
+	}
+	s.src, s.doc, s.index, s.ops = spec, doc, nil, make(map[string][]byte)
+	return nil
+}
+
+// indexJSON 返回文档索引：保留 info、tags、servers、安全方案等顶层信息，
+// paths 中每个接口只保留 summary、tags、deprecated、operationId、security
+func (s *operationStore) indexJSON(spec []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reset(spec); err != nil {
+		return nil, err
+	}
+	if s.index != nil {
+		return s.index, nil
+	}
+
+	index := make(map[string]interface{})
+	for k, v := range s.doc {
+		if !indexSkippedFields[k] {
+			index[k] = v
+		}
+	}
+	// 调试面板需要安全方案
+	if components, ok := s.doc["components"].(map[string]interface{}); ok {
+		if schemes, ok := components["securitySchemes"]; ok {
+			index["components"] = map[string]interface{}{"securitySchemes": schemes}
+		}
+	}
+
+	paths := make(map[string]interface{})
+	forEachOperation(s.doc, func(path, method string, op map[string]interface{}) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		summary := make(map[string]interface{})
+		for _, field := range indexOperationFields {
+			if v, ok := op[field]; ok {
+				summary[field] = v
+			}
+		}
+		item[method] = summary
+	})
+	index["paths"] = paths
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	s.index = data
+	return data, nil
+}
+
+// operationJSON 返回单个接口的详情：合并路径级参数并展开所有 $ref（递归 schema 输出循环引用标记）
+func (s *operationStore) operationJSON(spec []byte, method, path string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reset(spec); err != nil {
+		return nil, false, err
+	}
+	method = strings.ToLower(method)
+	key := method + " " + path
+	if data, ok := s.ops[key]; ok {
+		return data, true, nil
+	}
+
+	paths, _ := s.doc["paths"].(map[string]interface{})
+	item, _ := paths[path].(map[string]interface{})
+	op, ok := item[method].(map[string]interface{})
+	if !ok || !isHTTPMethod(method) {
+		return nil, false, nil
+	}
+	detail := copyMap(op)
+	if params := operationParameters(s.doc, path, op); len(params) > 0 {
+		list := make([]interface{}, len(params))
+		for i, p := range params {
+			list[i] = p
+		}
+		detail["parameters"] = list
+	}
+
+	d := &dereferencer{doc: s.doc, memo: make(map[string]interface{})}
+	data, err := json.Marshal(d.expand(detail))
+	if err != nil {
+		return nil, false, err
+	}
+	s.ops[key] = data
+	return data, true, nil
+}
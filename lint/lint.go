@@ -0,0 +1,242 @@
+// Package lint checks OpenAPI/Swagger documents against configurable quality rules
+// 文档质量检查：按可配置的规则检查 Swagger 2.0 / OpenAPI 3 文档，输出 text、JSON 与 SARIF 报告
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Severity 问题级别
+type Severity string
+
+const (
+	// SeverityError 错误
+	SeverityError Severity = "error"
+	// SeverityWarning 警告
+	SeverityWarning Severity = "warning"
+	// SeverityInfo 提示
+	SeverityInfo Severity = "info"
+	// SeverityOff 关闭规则
+	SeverityOff Severity = "off"
+)
+
+// rank 级别排序，数值越大越严重
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// Valid reports whether s is one of error, warning, info or off (是否为可用的级别)
+func (s Severity) Valid() bool {
+	return s.rank() > 0 || s == SeverityOff
+}
+
+// AtLeast reports whether s is at least as severe as other (s 是否不低于 other)
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank() && s.rank() > 0
+}
+
+// Finding 一条检查结果
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Pointer 问题所在位置的 JSON Pointer，如 "/paths/~1users/get"
+	Pointer string `json:"pointer"`
+	// Line 在文档文件中的行号（尽力定位，未知时为 0）
+	Line int `json:"line,omitempty"`
+}
+
+// Rule 检查规则
+type Rule struct {
+	ID          string
+	Description string
+	// Severity 默认级别，可被 Config.Rules 覆盖
+	Severity Severity
+	Check    func(doc *Document, cfg Config) []Finding
+}
+
+// Config 检查配置，可从 JSON 文件加载：
+//
+//	{"rules": {"operation-description": "off", "path-casing": "error"}, "pathCase": "kebab"}
+type Config struct {
+	// Rules 覆盖规则级别，设为 "off" 关闭规则
+	Rules map[string]Severity `json:"rules,omitempty"`
+	// PathCase 路径段命名风格：kebab、snake、camel；为空时以文档中占多数的风格为准
+	PathCase string `json:"pathCase,omitempty"`
+	// Extra 自定义规则
+	Extra []Rule `json:"-"`
+}
+
+// LoadConfig 从 JSON 文件加载检查配置
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("解析配置失败: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// validate 检查 Rules 中的级别，拼写错误（如 "warn"）会使规则被静默降级，因此直接报错
+func (c Config) validate() error {
+	ids := make([]string, 0, len(c.Rules))
+	for id := range c.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !c.Rules[id].Valid() {
+			return fmt.Errorf("规则 %s 的级别 %q 无效，可选 error、warning、info、off", id, c.Rules[id])
+		}
+	}
+	return nil
+}
+
+// Report 检查报告
+type Report struct {
+	Findings []Finding        `json:"findings"`
+	Summary  map[Severity]int `json:"summary"`
+	// Rules 本次运行的规则（用于 SARIF 输出）
+	Rules []Rule `json:"-"`
+}
+
+// Failed reports whether any finding is at least the given severity (是否存在不低于指定级别的问题)
+func (r *Report) Failed(threshold Severity) bool {
+	if threshold.rank() == 0 {
+		return false
+	}
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// Document 解析后的文档，供规则使用
+type Document struct {
+	Raw  []byte
+	Spec map[string]interface{}
+	// Swagger2 是否为 Swagger 2.0 文档
+	Swagger2 bool
+}
+
+// Operation 文档中的一个操作
+type Operation struct {
+	Path    string
+	Method  string
+	Pointer string
+	Op      map[string]interface{}
+}
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Operations 按路径与方法排序返回文档中的所有操作
+func (d *Document) Operations() []Operation {
+	paths, _ := d.Spec["paths"].(map[string]interface{})
+	keys := make([]string, 0, len(paths))
+	for p := range paths {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+
+	var ops []Operation
+	for _, p := range keys {
+		item, _ := paths[p].(map[string]interface{})
+		for _, m := range httpMethods {
+			if op, ok := item[m].(map[string]interface{}); ok {
+				ops = append(ops, Operation{Path: p, Method: m, Pointer: Pointer("paths", p, m), Op: op})
+			}
+		}
+	}
+	return ops
+}
+
+// Pointer 将路径片段转义并拼接为 JSON Pointer
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+	}
+	return b.String()
+}
+
+// Run 使用内置规则与 cfg.Extra 检查文档，cfg.Rules 中的级别无效时返回错误
+func Run(spec []byte, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("解析文档失败: %w", err)
+	}
+	d := &Document{Raw: spec, Spec: doc, Swagger2: doc["swagger"] != nil}
+
+	report := &Report{Findings: []Finding{}, Summary: map[Severity]int{}}
+	for _, rule := range append(DefaultRules(), cfg.Extra...) {
+		severity := rule.Severity
+		if s, ok := cfg.Rules[rule.ID]; ok {
+			severity = s
+		}
+		if severity == SeverityOff || severity == "" {
+			continue
+		}
+		report.Rules = append(report.Rules, rule)
+		for _, f := range rule.Check(d, cfg) {
+			f.Rule = rule.ID
+			f.Severity = severity
+			f.Line = locate(spec, f.Pointer)
+			report.Findings = append(report.Findings, f)
+			report.Summary[severity]++
+		}
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Pointer < b.Pointer
+	})
+	return report, nil
+}
+
+// locate 尽力计算 JSON Pointer 在文件中的行号：依次查找每一级的 key
+func locate(data []byte, pointer string) int {
+	if pointer == "" {
+		return 0
+	}
+	text := string(data)
+	offset := 0
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		key, _ := json.Marshal(token)
+		i := strings.Index(text[offset:], string(key))
+		if i < 0 {
+			break
+		}
+		offset += i
+	}
+	if offset == 0 {
+		return 0
+	}
+	return strings.Count(text[:offset], "\n") + 1
+}
@@ -0,0 +1,233 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lintSpec 两个接口：/users 完整，/orders 缺少 summary、description、tag 与错误响应，且 operationId 重复；Unused 未被引用
+const lintSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "lint", "version": "1.0.0"},
+  "paths": {
+    "/orders": {
+      "get": {
+        "operationId": "list",
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/users": {
+      "get": {
+        "summary": "用户列表",
+        "description": "分页返回用户",
+        "tags": ["User"],
+        "operationId": "list",
+        "responses": {
+          "200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "default": {"description": "error"}
+        }
+      }
+    }
+  },
+  "components": {"schemas": {
+    "User": {"type": "object"},
+    "Unused": {"type": "object"}
+  }}
+}`
+
+// findingsByRule 按规则分组检查结果
+func findingsByRule(report *Report) map[string][]Finding {
+	result := make(map[string][]Finding)
+	for _, f := range report.Findings {
+		result[f.Rule] = append(result[f.Rule], f)
+	}
+	return result
+}
+
+func TestRunDefaultRules(t *testing.T) {
+	report, err := Run([]byte(lintSpec), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byRule := findingsByRule(report)
+	want := map[string]Severity{
+		"operation-summary":         SeverityWarning,
+		"operation-description":     SeverityInfo,
+		"operation-tags":            SeverityWarning,
+		"operation-error-responses": SeverityWarning,
+		"operation-id-unique":       SeverityError,
+		"unused-components":         SeverityWarning,
+	}
+	for rule, severity := range want {
+		findings := byRule[rule]
+		if len(findings) != 1 {
+			t.Errorf("%s 结果数 = %d：%v", rule, len(findings), findings)
+			continue
+		}
+		if findings[0].Severity != severity {
+			t.Errorf("%s 级别 = %s", rule, findings[0].Severity)
+		}
+	}
+	if len(byRule) != len(want) {
+		t.Errorf("多余的规则结果：%v", byRule)
+	}
+	if f := byRule["operation-summary"][0]; f.Pointer != "/paths/~1orders/get" || f.Line != 6 {
+		t.Errorf("定位 = %s:%d", f.Pointer, f.Line)
+	}
+	if f := byRule["unused-components"][0]; !strings.Contains(f.Message, "Unused") {
+		t.Errorf("未使用组件 = %s", f.Message)
+	}
+	if report.Summary[SeverityError] != 1 || report.Summary[SeverityWarning] != 4 || report.Summary[SeverityInfo] != 1 {
+		t.Errorf("汇总 = %v", report.Summary)
+	}
+	for i := 1; i < len(report.Findings); i++ {
+		if report.Findings[i-1].Line > report.Findings[i].Line {
+			t.Fatalf("结果未按行号排序：%v", report.Findings)
+		}
+	}
+}
+
+func TestRunRuleOverrides(t *testing.T) {
+	cfg := Config{Rules: map[string]Severity{
+		"operation-description": SeverityOff,
+		"operation-summary":     SeverityError,
+		"operation-id-unique":   SeverityInfo,
+	}}
+	report, err := Run([]byte(lintSpec), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byRule := findingsByRule(report)
+	if len(byRule["operation-description"]) != 0 {
+		t.Error("关闭的规则仍有结果")
+	}
+	for _, rule := range report.Rules {
+		if rule.ID == "operation-description" {
+			t.Error("关闭的规则不应出现在 Rules 中")
+		}
+	}
+	if f := byRule["operation-summary"]; len(f) != 1 || f[0].Severity != SeverityError {
+		t.Errorf("operation-summary = %v", f)
+	}
+	if f := byRule["operation-id-unique"]; len(f) != 1 || f[0].Severity != SeverityInfo {
+		t.Errorf("operation-id-unique = %v", f)
+	}
+}
+
+func TestReportFailed(t *testing.T) {
+	report := &Report{Findings: []Finding{{Severity: SeverityWarning}}}
+	cases := map[Severity]bool{
+		SeverityError:   false,
+		SeverityWarning: true,
+		SeverityInfo:    true,
+		SeverityOff:     false,
+		"warn":          false,
+	}
+	for threshold, want := range cases {
+		if got := report.Failed(threshold); got != want {
+			t.Errorf("Failed(%q) = %v", threshold, got)
+		}
+	}
+}
+
+func TestInvalidSeverity(t *testing.T) {
+	_, err := Run([]byte(lintSpec), Config{Rules: map[string]Severity{"operation-summary": "warn"}})
+	if err == nil || !strings.Contains(err.Error(), "operation-summary") {
+		t.Errorf("Run 应拒绝无效级别，err = %v", err)
+	}
+
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{"rules": {"path-casing": "Error"}}`), 0o644)
+	if _, err := LoadConfig(bad); err == nil {
+		t.Error("LoadConfig 应拒绝无效级别")
+	}
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(`{"rules": {"path-casing": "off", "operation-tags": "error"}, "pathCase": "kebab"}`), 0o644)
+	cfg, err := LoadConfig(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Rules["operation-tags"] != SeverityError || cfg.PathCase != "kebab" {
+		t.Errorf("配置 = %+v", cfg)
+	}
+}
+
+func TestExtraRule(t *testing.T) {
+	rule := Rule{ID: "no-orders", Description: "不允许 /orders", Severity: SeverityError, Check: func(doc *Document, _ Config) []Finding {
+		var findings []Finding
+		for _, op := range doc.Operations() {
+			if op.Path == "/orders" {
+				findings = append(findings, Finding{Pointer: op.Pointer, Message: "禁止的路径"})
+			}
+		}
+		return findings
+	}}
+	report, err := Run([]byte(lintSpec), Config{Extra: []Rule{rule}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := findingsByRule(report)["no-orders"]; len(f) != 1 || f[0].Severity != SeverityError {
+		t.Errorf("自定义规则结果 = %v", f)
+	}
+	if !report.Failed(SeverityError) {
+		t.Error("应当失败")
+	}
+}
+
+func TestPathCasing(t *testing.T) {
+	spec := `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {
+	  "/user-profiles": {"get": {"responses": {"400": {"description": "e"}}}},
+	  "/order-items": {"get": {"responses": {"400": {"description": "e"}}}},
+	  "/audit_logs": {"get": {"responses": {"400": {"description": "e"}}}}
+	}}`
+	report, err := Run([]byte(spec), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := findingsByRule(report)["path-casing"]
+	if len(f) != 1 || !strings.Contains(f[0].Message, "audit_logs") {
+		t.Errorf("多数为 kebab 时 = %v", f)
+	}
+
+	report, _ = Run([]byte(spec), Config{PathCase: "snake"})
+	if f := findingsByRule(report)["path-casing"]; len(f) != 2 {
+		t.Errorf("指定 snake 时 = %v", f)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	report, err := Run([]byte(lintSpec), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := report.Write(&buf, "sarif", "openapi.json"); err != nil {
+		t.Fatal(err)
+	}
+	var sarif struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+	levels := make(map[string]string)
+	for _, r := range sarif.Runs[0].Results {
+		levels[r.RuleID] = r.Level
+	}
+	if levels["operation-id-unique"] != "error" || levels["operation-summary"] != "warning" || levels["operation-description"] != "note" {
+		t.Errorf("SARIF 级别 = %v", levels)
+	}
+	if err := report.Write(&buf, "xml", "openapi.json"); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+}
@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteText 以文本格式输出报告，file 为文档文件名（用于定位）
+func (r *Report) WriteText(w io.Writer, file string) error {
+	for _, f := range r.Findings {
+		location := file
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", file, f.Line)
+		}
+		if _, err := fmt.Fprintf(w, "%s %s [%s] %s\n", location, f.Severity, f.Rule, f.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d 个错误，%d 个警告，%d 个提示\n", r.Summary[SeverityError], r.Summary[SeverityWarning], r.Summary[SeverityInfo])
+	return err
+}
+
+// WriteJSON 以 JSON 格式输出报告
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteSARIF 以 SARIF 2.1.0 格式输出报告，供 GitHub Code Scanning 与编辑器使用
+func (r *Report) WriteSARIF(w io.Writer, file string) error {
+	rules := make([]map[string]interface{}, 0, len(r.Rules))
+	index := make(map[string]int)
+	for i, rule := range r.Rules {
+		index[rule.ID] = i
+		rules = append(rules, map[string]interface{}{
+			"id":                   rule.ID,
+			"shortDescription":     map[string]string{"text": rule.Description},
+			"defaultConfiguration": map[string]string{"level": sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := make([]map[string]interface{}, 0, len(r.Findings))
+	for _, f := range r.Findings {
+		physical := map[string]interface{}{
+			"artifactLocation": map[string]string{"uri": file},
+		}
+		if f.Line > 0 {
+			physical["region"] = map[string]int{"startLine": f.Line}
+		}
+		results = append(results, map[string]interface{}{
+			"ruleId":    f.Rule,
+			"ruleIndex": index[f.Rule],
+			"level":     sarifLevel(f.Severity),
+			"message":   map[string]string{"text": f.Message},
+			"locations": []map[string]interface{}{{
+				"physicalLocation": physical,
+				"logicalLocations": []map[string]string{{"fullyQualifiedName": f.Pointer}},
+			}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []map[string]interface{}{{
+			"tool": map[string]interface{}{
+				"driver": map[string]interface{}{
+					"name":           "qingfeng-lint",
+					"informationUri": "https://github.com/buyfakett/qingfeng",
+					"rules":          rules,
+				},
+			},
+			"results": results,
+		}},
+	})
+}
+
+// Write 按格式输出报告：text、json、sarif
+func (r *Report) Write(w io.Writer, format, file string) error {
+	switch strings.ToLower(format) {
+	case "", "text":
+		return r.WriteText(w, file)
+	case "json":
+		return r.WriteJSON(w)
+	case "sarif":
+		return r.WriteSARIF(w, file)
+	}
+	return fmt.Errorf("不支持的输出格式: %s", format)
+}
+
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,264 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultRules 内置规则
+func DefaultRules() []Rule {
+	return []Rule{
+		{ID: "operation-summary", Description: "接口应当有 summary", Severity: SeverityWarning, Check: checkSummary},
+		{ID: "operation-description", Description: "接口应当有 description", Severity: SeverityInfo, Check: checkDescription},
+		{ID: "operation-tags", Description: "接口应当至少有一个 tag", Severity: SeverityWarning, Check: checkTags},
+		{ID: "operation-error-responses", Description: "接口应当声明错误响应（4xx/5xx 或 default）", Severity: SeverityWarning, Check: checkErrorResponses},
+		{ID: "operation-id-unique", Description: "operationId 不能重复", Severity: SeverityError, Check: checkDuplicateOperationIDs},
+		{ID: "path-casing", Description: "路径段命名风格应当一致", Severity: SeverityWarning, Check: checkPathCasing},
+		{ID: "unused-components", Description: "不应存在未被引用的组件定义", Severity: SeverityWarning, Check: checkUnusedComponents},
+	}
+}
+
+func operationName(op Operation) string {
+	return strings.ToUpper(op.Method) + " " + op.Path
+}
+
+func getString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func checkSummary(doc *Document, _ Config) []Finding {
+	var findings []Finding
+	for _, op := range doc.Operations() {
+		if strings.TrimSpace(getString(op.Op, "summary")) == "" {
+			findings = append(findings, Finding{Pointer: op.Pointer, Message: operationName(op) + " 缺少 summary"})
+		}
+	}
+	return findings
+}
+
+func checkDescription(doc *Document, _ Config) []Finding {
+	var findings []Finding
+	for _, op := range doc.Operations() {
+		if strings.TrimSpace(getString(op.Op, "description")) == "" {
+			findings = append(findings, Finding{Pointer: op.Pointer, Message: operationName(op) + " 缺少 description"})
+		}
+	}
+	return findings
+}
+
+func checkTags(doc *Document, _ Config) []Finding {
+	var findings []Finding
+	for _, op := range doc.Operations() {
+		if tags, _ := op.Op["tags"].([]interface{}); len(tags) == 0 {
+			findings = append(findings, Finding{Pointer: op.Pointer, Message: operationName(op) + " 没有 tag，将无法在侧边栏中分组"})
+		}
+	}
+	return findings
+}
+
+func checkErrorResponses(doc *Document, _ Config) []Finding {
+	var findings []Finding
+	for _, op := range doc.Operations() {
+		responses, _ := op.Op["responses"].(map[string]interface{})
+		found := false
+		for code := range responses {
+			if code == "default" || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			findings = append(findings, Finding{Pointer: op.Pointer + "/responses", Message: operationName(op) + " 没有声明错误响应"})
+		}
+	}
+	return findings
+}
+
+func checkDuplicateOperationIDs(doc *Document, _ Config) []Finding {
+	seen := make(map[string]Operation)
+	var findings []Finding
+	for _, op := range doc.Operations() {
+		id := getString(op.Op, "operationId")
+		if id == "" {
+			continue
+		}
+		if first, ok := seen[id]; ok {
+			findings = append(findings, Finding{
+				Pointer: op.Pointer + "/operationId",
+				Message: fmt.Sprintf("%s 的 operationId %q 与 %s 重复", operationName(op), id, operationName(first)),
+			})
+			continue
+		}
+		seen[id] = op
+	}
+	return findings
+}
+
+// segmentCase 判断路径段的命名风格，单个小写单词与任何风格兼容，返回空字符串
+func segmentCase(segment string) string {
+	switch {
+	case strings.Contains(segment, "-"):
+		return "kebab"
+	case strings.Contains(segment, "_"):
+		return "snake"
+	}
+	for i, r := range segment {
+		if unicode.IsUpper(r) {
+			if i == 0 {
+				return "pascal"
+			}
+			return "camel"
+		}
+	}
+	return ""
+}
+
+func checkPathCasing(doc *Document, cfg Config) []Finding {
+	type segment struct {
+		path, value, style string
+	}
+	var segments []segment
+	counts := make(map[string]int)
+	paths, _ := doc.Spec["paths"].(map[string]interface{})
+	keys := make([]string, 0, len(paths))
+	for p := range paths {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+	for _, p := range keys {
+		for _, part := range strings.Split(strings.Trim(p, "/"), "/") {
+			if part == "" || strings.HasPrefix(part, "{") {
+				continue
+			}
+			if style := segmentCase(part); style != "" {
+				segments = append(segments, segment{path: p, value: part, style: style})
+				counts[style]++
+			}
+		}
+	}
+
+	expected := cfg.PathCase
+	if expected == "" {
+		// 以占多数的风格为准，数量相同时按名称排序取第一个
+		styles := make([]string, 0, len(counts))
+		for s := range counts {
+			styles = append(styles, s)
+		}
+		sort.Slice(styles, func(i, j int) bool {
+			if counts[styles[i]] != counts[styles[j]] {
+				return counts[styles[i]] > counts[styles[j]]
+			}
+			return styles[i] < styles[j]
+		})
+		if len(styles) < 2 {
+			return nil
+		}
+		expected = styles[0]
+	}
+
+	var findings []Finding
+	reported := make(map[string]bool)
+	for _, s := range segments {
+		if s.style == expected || reported[s.path] {
+			continue
+		}
+		reported[s.path] = true
+		findings = append(findings, Finding{
+			Pointer: Pointer("paths", s.path),
+			Message: fmt.Sprintf("路径 %s 的片段 %q 使用 %s 风格，应为 %s", s.path, s.value, s.style, expected),
+		})
+	}
+	return findings
+}
+
+// componentSections 可被 $ref 引用的组件位置
+func componentSections(doc *Document) map[string]map[string]interface{} {
+	sections := make(map[string]map[string]interface{})
+	if doc.Swagger2 {
+		for _, key := range []string{"definitions", "parameters", "responses"} {
+			if m, ok := doc.Spec[key].(map[string]interface{}); ok {
+				sections["#/"+key+"/"] = m
+			}
+		}
+		return sections
+	}
+	components, _ := doc.Spec["components"].(map[string]interface{})
+	for _, key := range []string{"schemas", "parameters", "responses", "requestBodies", "headers", "examples", "links", "callbacks"} {
+		if m, ok := components[key].(map[string]interface{}); ok {
+			sections["#/components/"+key+"/"] = m
+		}
+	}
+	return sections
+}
+
+func checkUnusedComponents(doc *Document, _ Config) []Finding {
+	sections := componentSections(doc)
+	if len(sections) == 0 {
+		return nil
+	}
+
+	// 从组件以外的部分出发，沿 $ref 查找所有可达的组件
+	used := make(map[string]bool)
+	var queue []string
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := val["$ref"].(string); ok && !used[ref] {
+				used[ref] = true
+				queue = append(queue, ref)
+			}
+			for _, item := range val {
+				collect(item)
+			}
+		case []interface{}:
+			for _, item := range val {
+				collect(item)
+			}
+		}
+	}
+	for key, v := range doc.Spec {
+		if key == "definitions" || key == "components" || (doc.Swagger2 && (key == "parameters" || key == "responses")) {
+			continue
+		}
+		collect(v)
+	}
+	// securitySchemes 通过名称而非 $ref 引用，不参与检查
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		for prefix, section := range sections {
+			if name := strings.TrimPrefix(ref, prefix); name != ref {
+				collect(section[name])
+			}
+		}
+	}
+
+	prefixes := make([]string, 0, len(sections))
+	for prefix := range sections {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	var findings []Finding
+	for _, prefix := range prefixes {
+		names := make([]string, 0, len(sections[prefix]))
+		for name := range sections[prefix] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if used[prefix+name] {
+				continue
+			}
+			tokens := strings.Split(strings.Trim(strings.TrimPrefix(prefix, "#"), "/"), "/")
+			findings = append(findings, Finding{
+				Pointer: Pointer(append(tokens, name)...),
+				Message: fmt.Sprintf("%s 未被引用", prefix+name),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,217 @@
+package qingfeng
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// localizedFields 支持多语言的文本字段
+var localizedFields = []string{"title", "summary", "description"}
+
+// localizeSkippedFields 值为示例数据的字段，不做本地化
+var localizeSkippedFields = map[string]bool{"example": true, "examples": true}
+
+// specLocalizer 按语言缓存本地化后的文档，文档变化时重新生成。
+// 文档中的翻译有两种写法，均可用于 info、tags、接口、参数与 schema 属性：
+//
+//	"x-i18n": {"en": {"summary": "Create user", "description": "..."}}
+//	"x-summary-en": "Create user", "x-description-en": "..."
+type specLocalizer struct {
+	mu        sync.Mutex
+	src       []byte
+	doc       map[string]interface{}
+	languages []string
+	out       map[string][]byte
+}
+
+// reset 文档内容变化时重新收集翻译的语言（调用方持有锁）
+func (l *specLocalizer) reset(spec []byte) {
+	if l.src != nil && bytes.Equal(l.src, spec) {
+		return
+	}
+	l.src, l.doc, l.out = spec, parseSpec(spec), make(map[string][]byte)
+	l.languages = specLanguages(l.doc)
+}
+
+// available 返回文档中有翻译的语言
+func (l *specLocalizer) available(spec []byte) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reset(spec)
+	return l.languages
+}
+
+// get 返回本地化为 lang 的文档，没有翻译的字段保留原文；lang 为空时返回原文档
+func (l *specLocalizer) get(spec []byte, lang string) []byte {
+	if lang == "" {
+		return spec
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reset(spec)
+	if l.doc == nil {
+		return spec
+	}
+	if data, ok := l.out[lang]; ok {
+		return data
+	}
+	data, err := json.Marshal(localizeValue(l.doc, lang))
+	if err != nil {
+		return spec
+	}
+	l.out[lang] = data
+	return data
+}
+
+// specLanguages 收集文档中所有翻译的语言代码（按名称排序）
+func specLanguages(doc map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var languages []string
+	add := func(code string) {
+		if code != "" && !seen[strings.ToLower(code)] {
+			seen[strings.ToLower(code)] = true
+			languages = append(languages, code)
+		}
+	}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			for key, child := range val {
+				if translations, ok := child.(map[string]interface{}); ok && key == "x-i18n" {
+					for code := range translations {
+						add(code)
+					}
+					continue
+				}
+				if _, code, ok := translationKey(key); ok {
+					add(code)
+					continue
+				}
+				if !localizeSkippedFields[key] {
+					walk(child)
+				}
+			}
+		case []interface{}:
+			for _, child := range val {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	sort.Strings(languages)
+	return languages
+}
+
+// localizeValue 复制文档并用 lang 的翻译替换文本字段，同时去掉翻译扩展字段
+func localizeValue(v interface{}, lang string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		translated := false
+		for key, child := range val {
+			if _, _, ok := translationKey(key); ok {
+				translated = true
+				continue
+			}
+			if _, ok := child.(map[string]interface{}); ok && key == "x-i18n" {
+				translated = true
+				continue
+			}
+			if localizeSkippedFields[key] {
+				out[key] = child
+				continue
+			}
+			out[key] = localizeValue(child, lang)
+		}
+		if translated {
+			for _, field := range localizedFields {
+				if text, ok := translatedText(val, field, lang); ok {
+					out[field] = text
+				}
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = localizeValue(child, lang)
+		}
+		return out
+	}
+	return v
+}
+
+// translatedText 返回字段在 lang 下的翻译：先找完全匹配的语言，再找主语言相同的语言（en-GB → en）
+func translatedText(obj map[string]interface{}, field, lang string) (string, bool) {
+	translations, _ := obj["x-i18n"].(map[string]interface{})
+	for _, exact := range []bool{true, false} {
+		for _, code := range sortedKeys(translations) {
+			if !languageMatches(code, lang, exact) {
+				continue
+			}
+			if fields, ok := translations[code].(map[string]interface{}); ok {
+				if text, ok := fields[field].(string); ok {
+					return text, true
+				}
+			}
+		}
+		for _, key := range sortedKeys(obj) {
+			if f, code, ok := translationKey(key); ok && f == field && languageMatches(code, lang, exact) {
+				if text, ok := obj[key].(string); ok {
+					return text, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// translationKey 解析 x-<field>-<lang> 形式的翻译字段，如 x-description-en、x-summary-zh-CN
+func translationKey(key string) (field, lang string, ok bool) {
+	for _, f := range localizedFields {
+		if code, found := strings.CutPrefix(key, "x-"+f+"-"); found && code != "" {
+			return f, code, true
+		}
+	}
+	return "", "", false
+}
+
+// languageMatches 判断翻译的语言代码是否适用于 lang
+func languageMatches(code, lang string, exact bool) bool {
+	if exact {
+		return strings.EqualFold(code, lang)
+	}
+	return baseLanguage(code) == baseLanguage(lang)
+}
+
+// specVariant 一种语言的文档派生数据（展开引用的文档、按需加载索引、搜索索引）
+type specVariant struct {
+	deref      derefCache
+	operations operationStore
+	searches   searchStore
+}
+
+// specVariants 按语言保存派生数据，切换语言时不会互相覆盖缓存
+type specVariants struct {
+	mu    sync.Mutex
+	items map[string]*specVariant
+}
+
+// get 返回 lang 的派生数据，lang 为空表示原文档
+func (v *specVariants) get(lang string) *specVariant {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.items == nil {
+		v.items = make(map[string]*specVariant)
+	}
+	item, ok := v.items[lang]
+	if !ok {
+		item = &specVariant{}
+		v.items[lang] = item
+	}
+	return item
+}
@@ -0,0 +1,701 @@
+package qingfeng
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// headingIDPrefix 页面标题锚点的前缀，避免与界面元素的 id 冲突
+const headingIDPrefix = "guide-"
+
+// urlScheme 匹配带协议的链接，如 https:、mailto:、javascript:
+var urlScheme = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// tableSeparator 匹配表格的分隔行，如 | --- | :---: |
+var tableSeparator = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+
+// orderedMarker 匹配有序列表的序号，如 "1. "、"2) "
+var orderedMarker = regexp.MustCompile(`^(\d{1,9})[.)]( |$)`)
+
+// markdownRenderer 将 Markdown 渲染为 HTML，支持标题、段落、强调、行内代码、代码块、链接、图片、
+// 列表、引用、表格与分隔线。原始 HTML 一律转义；链接的改写规则见 linkAttrs
+type markdownRenderer struct {
+	// dir 当前页面所在目录（相对页面根目录），用于解析相对链接
+	dir string
+	// slugs 页面文件路径 → 页面 slug，用于把 .md 链接改写为页面跳转
+	slugs map[string]string
+	// title 第一个一级标题的纯文本
+	title    string
+	headings []string
+	ids      map[string]int
+}
+
+// render 渲染整篇 Markdown
+func (m *markdownRenderer) render(src string) string {
+	var b strings.Builder
+	m.blocks(&b, strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"), false)
+	return b.String()
+}
+
+// blocks 渲染块级元素；tight 为 true 时段落不包裹 <p>（紧凑列表项）
+func (m *markdownRenderer) blocks(b *strings.Builder, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+		case isFence(trimmed):
+			i = m.codeBlock(b, lines, i)
+		case headingLevel(trimmed) > 0:
+			m.heading(b, trimmed)
+			i++
+		case isRule(trimmed):
+			b.WriteString("<hr>\n")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			i = m.blockquote(b, lines, i)
+		case isListItem(line):
+			i = m.list(b, lines, i)
+		case i+1 < len(lines) && strings.Contains(line, "|") && isTableSeparator(lines[i+1]):
+			i = m.table(b, lines, i)
+		default:
+			i = m.paragraph(b, lines, i, tight)
+		}
+	}
+}
+
+// startsBlock 判断一行是否开始新的块（可打断段落）
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || isFence(trimmed) || headingLevel(trimmed) > 0 || isRule(trimmed) ||
+		strings.HasPrefix(trimmed, ">") || isListItem(line)
+}
+
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// headingLevel 返回 ATX 标题的级别，不是标题时返回 0
+func headingLevel(trimmed string) int {
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// isRule 判断是否为分隔线：--- *** ___（可含空格）
+func isRule(trimmed string) bool {
+	s := strings.ReplaceAll(trimmed, " ", "")
+	if len(s) < 3 || !strings.ContainsRune("-*_", rune(s[0])) {
+		return false
+	}
+	return strings.Trim(s, s[:1]) == ""
+}
+
+func isTableSeparator(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.Contains(trimmed, "-") && tableSeparator.MatchString(trimmed)
+}
+
+// listMarker 解析列表项标记，返回缩进、内容起始列与是否有序
+type listMarker struct {
+	indent  int
+	width   int
+	ordered bool
+	start   string
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	rest := strings.TrimLeft(line, " ")
+	indent := len(line) - len(rest)
+	if len(rest) >= 1 && strings.ContainsRune("-*+", rune(rest[0])) && (len(rest) == 1 || rest[1] == ' ') {
+		return listMarker{indent: indent, width: indent + min(len(rest), 2)}, true
+	}
+	if match := orderedMarker.FindStringSubmatch(rest); match != nil {
+		return listMarker{indent: indent, width: indent + len(match[0]), ordered: true, start: match[1]}, true
+	}
+	return listMarker{}, false
+}
+
+func isListItem(line string) bool {
+	_, ok := parseListMarker(line)
+	return ok
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// codeBlock 渲染围栏代码块，返回下一行的序号
+func (m *markdownRenderer) codeBlock(b *strings.Builder, lines []string, i int) int {
+	trimmed := strings.TrimSpace(lines[i])
+	fence := strings.Repeat(trimmed[:1], len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1])))
+	info := strings.Fields(trimmed[len(fence):])
+	var code []string
+	for i++; i < len(lines); i++ {
+		closing := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	b.WriteString("<pre><code")
+	if len(info) > 0 {
+		fmt.Fprintf(b, ` class="language-%s"`, html.EscapeString(info[0]))
+	}
+	b.WriteString(">")
+	b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// heading 渲染标题，并生成带前缀的锚点
+func (m *markdownRenderer) heading(b *strings.Builder, trimmed string) {
+	level := headingLevel(trimmed)
+	text := strings.TrimSpace(trimmed[level:])
+	// 去掉可选的结尾 #
+	if stripped := strings.TrimRight(text, "#"); stripped != text && (stripped == "" || strings.HasSuffix(stripped, " ")) {
+		text = strings.TrimSpace(stripped)
+	}
+	content := m.inline(text)
+	plain := plainText(content)
+	if level == 1 && m.title == "" {
+		m.title = plain
+	}
+	m.headings = append(m.headings, plain)
+	fmt.Fprintf(b, "<h%d id=\"%s\">%s</h%d>\n", level, html.EscapeString(m.headingID(plain)), content, level)
+}
+
+// headingID 返回标题的锚点 id，重复的标题依次追加 -1、-2
+func (m *markdownRenderer) headingID(text string) string {
+	id := anchorName(text)
+	if m.ids == nil {
+		m.ids = make(map[string]int)
+	}
+	n := m.ids[id]
+	m.ids[id] = n + 1
+	if n > 0 {
+		id = fmt.Sprintf("%s-%d", id, n)
+	}
+	return headingIDPrefix + id
+}
+
+// anchorName 将标题转换为锚点名：小写，保留字母与数字（含中文），空白与连字符转为 -
+func anchorName(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '_':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// blockquote 渲染引用块
+func (m *markdownRenderer) blockquote(b *strings.Builder, lines []string, i int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		inner = append(inner, strings.TrimPrefix(trimmed, " "))
+	}
+	b.WriteString("<blockquote>\n")
+	m.blocks(b, inner, false)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+// list 渲染列表：缩进到内容列的行属于当前列表项（可嵌套），列表项之间有空行时段落包裹 <p>
+func (m *markdownRenderer) list(b *strings.Builder, lines []string, i int) int {
+	first, _ := parseListMarker(lines[i])
+	var items [][]string
+	width := first.width
+	loose := false
+	for i < len(lines) {
+		line := lines[i]
+		if marker, ok := parseListMarker(line); ok && marker.indent < width {
+			if marker.ordered != first.ordered {
+				break
+			}
+			width = marker.width
+			items = append(items, []string{line[min(len(line), marker.width):]})
+			i++
+			continue
+		}
+		last := len(items) - 1
+		if strings.TrimSpace(line) == "" {
+			// 空行之后仍是列表项或缩进的内容时，列表继续
+			j := i + 1
+			for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+				j++
+			}
+			if j < len(lines) && (indentOf(lines[j]) >= width || isSiblingItem(lines[j], first, width)) {
+				if indentOf(lines[j]) < width {
+					loose = true
+				}
+				items[last] = append(items[last], "")
+				i++
+				continue
+			}
+			break
+		}
+		if indentOf(line) >= width {
+			items[last] = append(items[last], line[width:])
+			i++
+			continue
+		}
+		// 段落的延续行
+		if !startsBlock(line) && items[last][len(items[last])-1] != "" {
+			items[last] = append(items[last], strings.TrimSpace(line))
+			i++
+			continue
+		}
+		break
+	}
+
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag)
+	if first.ordered && strings.TrimLeft(first.start, "0") != "1" {
+		fmt.Fprintf(b, ` start="%s"`, strings.TrimLeft(first.start, "0"))
+	}
+	b.WriteString(">\n")
+	for _, item := range items {
+		b.WriteString("<li>")
+		m.blocks(b, item, !loose)
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func isSiblingItem(line string, first listMarker, width int) bool {
+	marker, ok := parseListMarker(line)
+	return ok && marker.indent < width && marker.ordered == first.ordered
+}
+
+// table 渲染 GFM 表格
+func (m *markdownRenderer) table(b *strings.Builder, lines []string, i int) int {
+	header := splitTableRow(lines[i])
+	var aligns []string
+	for _, cell := range splitTableRow(lines[i+1]) {
+		align := ""
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			align = "center"
+		case strings.HasSuffix(cell, ":"):
+			align = "right"
+		case strings.HasPrefix(cell, ":"):
+			align = "left"
+		}
+		aligns = append(aligns, align)
+	}
+	row := func(cells []string, tag string) {
+		b.WriteString("<tr>")
+		for j := range header {
+			cell := ""
+			if j < len(cells) {
+				cell = cells[j]
+			}
+			b.WriteString("<" + tag)
+			if j < len(aligns) && aligns[j] != "" {
+				fmt.Fprintf(b, ` style="text-align: %s"`, aligns[j])
+			}
+			b.WriteString(">" + m.inline(cell) + "</" + tag + ">")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("<table>\n<thead>\n")
+	row(header, "th")
+	b.WriteString("</thead>\n<tbody>\n")
+	for i += 2; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" || !strings.Contains(lines[i], "|") {
+			break
+		}
+		row(splitTableRow(lines[i]), "td")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return i
+}
+
+// splitTableRow 按未转义的 | 拆分表格行
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	start := 0
+	for j := 0; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case '|':
+			cells = append(cells, strings.TrimSpace(line[start:j]))
+			start = j + 1
+		}
+	}
+	return append(cells, strings.TrimSpace(line[start:]))
+}
+
+// paragraph 渲染段落，遇到空行或新的块时结束
+func (m *markdownRenderer) paragraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	var text []string
+	for ; i < len(lines); i++ {
+		if len(text) > 0 && startsBlock(lines[i]) {
+			break
+		}
+		text = append(text, strings.TrimLeft(lines[i], " "))
+	}
+	content := m.inline(strings.TrimRight(strings.Join(text, "\n"), " "))
+	if tight {
+		b.WriteString(content)
+		return i
+	}
+	b.WriteString("<p>" + content + "</p>\n")
+	return i
+}
+
+// inline 渲染行内元素：转义、强调、删除线、行内代码、链接、图片、自动链接与换行
+func (m *markdownRenderer) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+			continue
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '\n':
+			// 行尾两个空格表示强制换行
+			if strings.HasSuffix(s[:i], "  ") {
+				b.WriteString("<br>")
+			}
+			b.WriteByte('\n')
+			i++
+			continue
+		case c == '`':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			if end := closingBackticks(s, i+n, n); end >= 0 {
+				code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i = end + n
+				continue
+			}
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if text, dest, title, end, ok := parseLink(s, i+1); ok {
+				b.WriteString(m.image(text, dest, title))
+				i = end
+				continue
+			}
+		case c == '[':
+			if text, dest, title, end, ok := parseLink(s, i); ok {
+				b.WriteString(m.link(m.inline(text), dest, title))
+				i = end
+				continue
+			}
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				target := s[i+1 : i+end]
+				lower := strings.ToLower(target)
+				if !strings.ContainsAny(target, " \n<") && (strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")) {
+					b.WriteString(m.link(html.EscapeString(target), target, ""))
+					i += end + 1
+					continue
+				}
+			}
+		case c == '*' || c == '_' || c == '~':
+			if out, end, ok := m.emphasis(s, i); ok {
+				b.WriteString(out)
+				i = end
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// closingBackticks 查找与开头长度相同的反引号串，未找到时返回 -1
+func closingBackticks(s string, from, n int) int {
+	for j := from; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		k := j
+		for k < len(s) && s[k] == '`' {
+			k++
+		}
+		if k-j == n {
+			return j
+		}
+		j = k
+	}
+	return -1
+}
+
+// emphasis 渲染 *em*、**strong**、_em_、__strong__ 与 ~~del~~
+func (m *markdownRenderer) emphasis(s string, i int) (string, int, bool) {
+	c := s[i]
+	n := len(s[i:]) - len(strings.TrimLeft(s[i:], s[i:i+1]))
+	if c == '~' && n != 2 || n > 3 {
+		return "", 0, false
+	}
+	if n == 3 {
+		// ***text*** 同时加粗与倾斜
+		if end := strings.Index(s[i+3:], s[i:i+3]); end > 0 && s[i+3] != ' ' && s[i+2+end] != ' ' {
+			return "<em><strong>" + m.inline(s[i+3:i+3+end]) + "</strong></em>", i + 6 + end, true
+		}
+		n = 2
+	}
+	// _ 在单词内部不表示强调，如 snake_case
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", 0, false
+	}
+	start := i + n
+	if start >= len(s) || s[start] == ' ' || s[start] == '\n' {
+		return "", 0, false
+	}
+	delim := s[i : i+n]
+	for j := start + 1; j+n <= len(s); j++ {
+		if s[j] == '`' {
+			// 强调内的行内代码整体跳过
+			run := len(s[j:]) - len(strings.TrimLeft(s[j:], "`"))
+			if end := closingBackticks(s, j+run, run); end >= 0 {
+				j = end + run - 1
+			}
+			continue
+		}
+		if s[j:j+n] != delim || s[j-1] == ' ' || s[j-1] == '\n' {
+			continue
+		}
+		if n == 1 && (j+1 < len(s) && s[j+1] == c || s[j-1] == c) {
+			// 单个分隔符跳过内部的 ** 组合
+			j++
+			continue
+		}
+		if c == '_' && j+n < len(s) && isWordByte(s[j+n]) {
+			continue
+		}
+		tag := "em"
+		switch {
+		case c == '~':
+			tag = "del"
+		case n == 2:
+			tag = "strong"
+		}
+		return "<" + tag + ">" + m.inline(s[start:j]) + "</" + tag + ">", j + n, true
+	}
+	return "", 0, false
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < 0x80 && (unicode.IsPunct(rune(c)) || unicode.IsSymbol(rune(c)))
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// parseLink 解析 [text](dest "title")，s[i] 为 '['；dest 可包含空格（如 op:GET /users/{id}），
+// 也可写作 <dest>
+func parseLink(s string, i int) (text, dest, title string, end int, ok bool) {
+	depth := 0
+	j := i
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j+1 >= len(s) || s[j] != ']' || s[j+1] != '(' {
+		return "", "", "", 0, false
+	}
+	text = s[i+1 : j]
+	k := j + 2
+	depth = 1
+	for ; k < len(s); k++ {
+		if s[k] == '\n' {
+			return "", "", "", 0, false
+		}
+		if s[k] == '(' {
+			depth++
+		} else if s[k] == ')' {
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	if k >= len(s) {
+		return "", "", "", 0, false
+	}
+	inner := strings.TrimSpace(s[j+2 : k])
+	if strings.HasSuffix(inner, `"`) {
+		if q := strings.LastIndex(inner[:len(inner)-1], ` "`); q >= 0 {
+			title = inner[q+2 : len(inner)-1]
+			inner = strings.TrimSpace(inner[:q])
+		}
+	}
+	if strings.HasPrefix(inner, "<") && strings.HasSuffix(inner, ">") {
+		inner = inner[1 : len(inner)-1]
+	}
+	return text, inner, title, k + 1, true
+}
+
+// link 渲染链接，label 为已渲染的 HTML
+func (m *markdownRenderer) link(label, dest, title string) string {
+	attrs := m.linkAttrs(dest)
+	if title != "" {
+		attrs += ` title="` + html.EscapeString(title) + `"`
+	}
+	return "<a" + attrs + ">" + label + "</a>"
+}
+
+// linkAttrs 改写链接：
+//
+//	op:createUser、op:POST /users   跳转到接口（data-op，由界面按 operationId 或方法与路径查找）
+//	page:auth、auth.md#token        跳转到其他页面（data-page、data-anchor）
+//	#token                          页面内锚点
+//	https://...、mailto:...         外部链接，在新窗口打开
+//	images/flow.png                 页面目录中的文件（/pages/...）
+//
+// 其他协议（如 javascript:）的链接会被移除
+func (m *markdownRenderer) linkAttrs(dest string) string {
+	lower := strings.ToLower(dest)
+	switch {
+	case strings.HasPrefix(lower, "op:"):
+		return ` href="#" data-op="` + html.EscapeString(strings.TrimSpace(dest[3:])) + `"`
+	case strings.HasPrefix(lower, "page:"):
+		slug, anchor, _ := strings.Cut(strings.TrimSpace(dest[5:]), "#")
+		return pageLinkAttrs(slug, anchor)
+	case strings.HasPrefix(dest, "#"):
+		return ` href="#` + html.EscapeString(headingIDPrefix+anchorName(dest[1:])) + `"`
+	case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(dest, "//"):
+		return ` href="` + html.EscapeString(dest) + `" target="_blank" rel="noopener noreferrer"`
+	case strings.HasPrefix(lower, "mailto:") || strings.HasPrefix(dest, "/"):
+		return ` href="` + html.EscapeString(dest) + `"`
+	case urlScheme.MatchString(dest):
+		return ` href="#"`
+	}
+	file, anchor, _ := strings.Cut(dest, "#")
+	resolved, ok := m.resolve(file)
+	if !ok {
+		return ` href="#"`
+	}
+	if strings.EqualFold(path.Ext(resolved), ".md") {
+		slug, found := m.slugs[resolved]
+		if !found {
+			slug = pageSlug(resolved)
+		}
+		return pageLinkAttrs(slug, anchor)
+	}
+	return ` href="pages/` + html.EscapeString(resolved) + `" target="_blank"`
+}
+
+func pageLinkAttrs(slug, anchor string) string {
+	attrs := ` href="#" data-page="` + html.EscapeString(slug) + `"`
+	if anchor != "" {
+		attrs += ` data-anchor="` + html.EscapeString(headingIDPrefix+anchorName(anchor)) + `"`
+	}
+	return attrs
+}
+
+// resolve 将相对链接解析为页面根目录下的路径，越出根目录时返回 false
+func (m *markdownRenderer) resolve(file string) (string, bool) {
+	resolved := path.Join(m.dir, file)
+	if file == "" || resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", false
+	}
+	return resolved, true
+}
+
+// image 渲染图片：允许 http(s)、data:image/ 与页面目录中的相对路径，其他地址只显示替代文本
+func (m *markdownRenderer) image(alt, src, title string) string {
+	lower := strings.ToLower(src)
+	switch {
+	case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "data:image/") || strings.HasPrefix(src, "/"):
+	case urlScheme.MatchString(src):
+		return html.EscapeString(alt)
+	default:
+		resolved, ok := m.resolve(src)
+		if !ok {
+			return html.EscapeString(alt)
+		}
+		src = "pages/" + resolved
+	}
+	out := `<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(plainText(m.inline(alt))) + `" loading="lazy"`
+	if title != "" {
+		out += ` title="` + html.EscapeString(title) + `"`
+	}
+	return out + ">"
+}
+
+// blockTags 块级元素，提取纯文本时在其前后断开
+var blockTags = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "li": true, "ul": true, "ol": true,
+	"pre": true, "blockquote": true, "table": true, "tr": true, "th": true, "td": true, "br": true, "hr": true,
+}
+
+// plainText 去掉 HTML 标签并还原实体，得到用于标题与搜索的纯文本
+func plainText(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			b.WriteString(s)
+			break
+		}
+		end := strings.IndexByte(s[start:], '>')
+		if end < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:start])
+		name := strings.TrimPrefix(s[start+1:start+end], "/")
+		if name, _, _ = strings.Cut(name, " "); blockTags[name] {
+			b.WriteByte(' ')
+		}
+		s = s[start+end+1:]
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(b.String())), " ")
+}
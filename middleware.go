@@ -0,0 +1,215 @@
+package qingfeng
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ValidationConfig configures ValidationMiddleware
+// 请求/响应校验中间件配置
+type ValidationConfig struct {
+	// LogOnly only reports violations instead of rejecting the request (仅记录，不拦截请求)
+	LogOnly bool
+	// ValidateResponses validates response bodies against the spec, recommended for development only
+	// 校验响应体是否符合文档（会缓冲整个响应，建议仅在开发环境开启）
+	ValidateResponses bool
+	// Prefixes are extra path prefixes stripped before matching, e.g. "/api/v1"
+	// 匹配前额外去掉的路径前缀（文档 basePath/servers 会自动处理）
+	Prefixes []string
+	// Skip skips validation for matching requests (返回 true 时跳过校验)
+	Skip func(r *http.Request) bool
+	// OnViolation is called with every violation list, defaults to log.Printf
+	// 发现校验错误时的回调，默认使用 log.Printf 输出
+	OnViolation func(r *http.Request, violations []ValidationError)
+}
+
+// ValidationFailure is the JSON body returned when validation fails
+// 校验失败时返回的响应体
+type ValidationFailure struct {
+	Error      string            `json:"error"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Violations []ValidationError `json:"violations"`
+}
+
+// ValidationMiddleware returns a net/http middleware that validates requests against the spec
+// 返回按文档校验请求的 net/http 中间件：路径、查询、请求头、Cookie 参数与 JSON/表单请求体。
+// 未在文档中定义的路由直接放行；校验失败时返回 400（LogOnly 时仅记录）。
+//
+// 使用示例:
+//   - 标准库/Chi: handler = qingfeng.ValidationMiddleware(specJSON)(handler)
+//   - Gin: r.Use(qingfeng.GinValidationMiddleware(specJSON))
+//   - Echo: e.Use(echo.WrapMiddleware(qingfeng.ValidationMiddleware(specJSON)))
+//   - Fiber: app.Use(adaptor.HTTPMiddleware(qingfeng.ValidationMiddleware(specJSON)))
+func ValidationMiddleware(spec []byte, cfg ...ValidationConfig) func(http.Handler) http.Handler {
+	v := &validator{spec: normalizeSpec(spec)}
+	v.routes = newRouteIndex(v.spec)
+	if len(cfg) > 0 {
+		v.cfg = cfg[0]
+	}
+	if v.cfg.OnViolation == nil {
+		v.cfg.OnViolation = logViolations
+	}
+	return v.wrap
+}
+
+// GinValidationMiddleware returns ValidationMiddleware as a Gin middleware
+// 返回 Gin 框架的校验中间件
+func GinValidationMiddleware(spec []byte, cfg ...ValidationConfig) gin.HandlerFunc {
+	mw := ValidationMiddleware(spec, cfg...)
+	return func(c *gin.Context) {
+		passed := false
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			passed = true
+			c.Request = r
+			if rec, ok := w.(*responseRecorder); ok {
+				origin := c.Writer
+				c.Writer = &ginRecorder{ResponseWriter: origin, rec: rec}
+				defer func() { c.Writer = origin }()
+			}
+			c.Next()
+		})).ServeHTTP(c.Writer, c.Request)
+		if !passed {
+			c.Abort()
+		}
+	}
+}
+
+// validator 校验中间件的运行时状态
+type validator struct {
+	cfg    ValidationConfig
+	spec   map[string]interface{}
+	routes *routeIndex
+}
+
+func (v *validator) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v.spec == nil || (v.cfg.Skip != nil && v.cfg.Skip(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		match := v.routes.matchOperation(r.Method, pathCandidates(v.spec, r.URL.Path, v.cfg.Prefixes))
+		if match == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if errs := validateRequest(v.spec, match, r); len(errs) > 0 {
+			v.cfg.OnViolation(r, errs)
+			if !v.cfg.LogOnly {
+				writeJSON(w, http.StatusBadRequest, ValidationFailure{Error: "请求参数校验失败", Method: r.Method, Path: match.Path, Violations: errs})
+				return
+			}
+		}
+
+		if !v.cfg.ValidateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{header: make(http.Header), w: w}
+		next.ServeHTTP(rec, r)
+		if rec.hijacked {
+			return
+		}
+		status := rec.statusCode()
+		if errs := validateResponse(v.spec, match, status, rec.header.Get("Content-Type"), rec.body.Bytes()); len(errs) > 0 {
+			v.cfg.OnViolation(r, errs)
+			if !v.cfg.LogOnly {
+				writeJSON(w, http.StatusInternalServerError, ValidationFailure{Error: "响应校验失败", Method: r.Method, Path: match.Path, Violations: errs})
+				return
+			}
+		}
+		rec.flushTo(w)
+	})
+}
+
+// logViolations 默认的校验错误输出
+func logViolations(r *http.Request, violations []ValidationError) {
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.Error()
+	}
+	log.Printf("[QingFeng] %s %s 校验失败: %s\n", r.Method, r.URL.Path, strings.Join(msgs, "; "))
+}
+
+// responseRecorder 缓冲响应以便在写出前校验
+type responseRecorder struct {
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	hijacked bool
+	w        http.ResponseWriter // Hijack 时使用的原始 ResponseWriter
+}
+
+func (rec *responseRecorder) Header() http.Header { return rec.header }
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+// Flush 响应在校验完成后才写出，这里无需处理
+func (rec *responseRecorder) Flush() {}
+
+// Hijack 接管连接（如 WebSocket）时跳过响应校验
+func (rec *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := rec.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	rec.hijacked = true
+	return hj.Hijack()
+}
+
+func (rec *responseRecorder) statusCode() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+// flushTo 将缓冲的响应写入真正的 ResponseWriter
+func (rec *responseRecorder) flushTo(w http.ResponseWriter) {
+	for k, v := range rec.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.statusCode())
+	w.Write(rec.body.Bytes())
+}
+
+// ginRecorder 让 Gin 处理器写入 responseRecorder，同时满足 gin.ResponseWriter 接口
+type ginRecorder struct {
+	gin.ResponseWriter
+	rec *responseRecorder
+}
+
+func (g *ginRecorder) Header() http.Header         { return g.rec.Header() }
+func (g *ginRecorder) WriteHeader(status int)      { g.rec.WriteHeader(status) }
+func (g *ginRecorder) WriteHeaderNow()             { g.rec.WriteHeader(g.Status()) }
+func (g *ginRecorder) Write(b []byte) (int, error) { return g.rec.Write(b) }
+func (g *ginRecorder) WriteString(s string) (int, error) {
+	return g.rec.Write([]byte(s))
+}
+func (g *ginRecorder) Status() int   { return g.rec.statusCode() }
+func (g *ginRecorder) Size() int     { return g.rec.body.Len() }
+func (g *ginRecorder) Written() bool { return g.rec.status != 0 }
+func (g *ginRecorder) Flush()        {}
+func (g *ginRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	g.rec.hijacked = true
+	return g.ResponseWriter.Hijack()
+}
@@ -0,0 +1,343 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MockConfig 模拟服务配置
+// 根据文档中的路径、方法与响应定义返回模拟数据，供前端在后端未完成时联调
+type MockConfig struct {
+	// Prefix 模拟接口的路由前缀，相对于 BasePath（默认 "/mock"，即 /doc/mock/*）
+	Prefix string
+	// DisableValidation 关闭请求参数与请求体校验
+	DisableValidation bool
+	// Faults 全局故障注入（延迟、错误、断开连接、截断响应）
+	Faults MockFault
+	// Operations 按接口覆盖故障配置，key 为 "GET /users/{id}" 或 operationId
+	Operations map[string]MockFault
+	// Seed 随机数种子，非 0 时故障注入结果可复现
+	Seed int64
+	// AllowedOrigins 允许跨域调用模拟接口的来源（写法同 SecurityConfig.AllowedOrigins），为空时允许任意来源；
+	// 经 HTTPHandler 提供且配置了 Config.Security 时，为空则沿用 Security 的 AllowedOrigins 与 AllowCredentials
+	AllowedOrigins []string
+	// AllowCredentials 允许跨域请求携带 Cookie 等凭证（此时响应回显请求的 Origin）
+	AllowCredentials bool
+}
+
+// mockServer 基于文档生成模拟响应
+type mockServer struct {
+	cfg    MockConfig
+	spec   map[string]interface{} // 统一转换为 OpenAPI 3 格式的文档
+	routes *routeIndex
+	faults *faultInjector
+	// anyOrigin 允许任意来源跨域调用（未限制来源时）
+	anyOrigin bool
+}
+
+// MockHandler 返回根据文档生成模拟响应的 http.Handler
+// 请求路径为文档中的接口路径（可带 basePath/servers 前缀）。
+//
+// 响应来源优先级：examples/example > schema 自动生成；
+// 可通过请求头 Prefer 选择状态码或命名示例，如 "Prefer: code=404" 或 "Prefer: example=admin"
+//
+// 故障注入配置优先级：X-Mock-* 请求头 > cfg.Operations > 操作上的 x-mock-* 扩展 > cfg.Faults > 文档根级 x-mock-* 扩展
+func MockHandler(spec []byte, cfg MockConfig) http.Handler {
+	return newMockServer(spec, cfg, nil)
+}
+
+// newMockServer 创建模拟服务；security 不为 nil 且未单独配置来源时，跨域策略沿用文档的安全配置
+func newMockServer(spec []byte, cfg MockConfig, security *SecurityConfig) *mockServer {
+	restricted := len(cfg.AllowedOrigins) > 0
+	if !restricted && security != nil {
+		cfg.AllowedOrigins, cfg.AllowCredentials = security.AllowedOrigins, security.AllowCredentials
+		restricted = true
+	}
+	anyOrigin := !restricted || containsString(cfg.AllowedOrigins, "*") && !cfg.AllowCredentials
+	doc := normalizeSpec(spec)
+	return &mockServer{cfg: cfg, spec: doc, routes: newRouteIndex(doc), faults: newFaultInjector(cfg.Seed), anyOrigin: anyOrigin}
+}
+
+// allowCORS 写出跨域响应头，来源不被允许时返回 false
+func (m *mockServer) allowCORS(w http.ResponseWriter, r *http.Request) bool {
+	if m.anyOrigin {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return true
+	}
+	w.Header().Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" || !matchOrigin(m.cfg.AllowedOrigins, origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if m.cfg.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+func (m *mockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	allowed := m.allowCORS(w, r)
+	if m.spec == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "API documentation not found"})
+		return
+	}
+
+	candidates := pathCandidates(m.spec, r.URL.Path, nil)
+
+	// CORS 预检请求
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		if !allowed {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(m.routes.allowedMethods(candidates), ", "))
+		if h := r.Header.Get("Access-Control-Request-Headers"); h != "" {
+			w.Header().Set("Access-Control-Allow-Headers", h)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	match := m.routes.matchOperation(r.Method, candidates)
+	if match == nil {
+		if methods := m.routes.allowedMethods(candidates); len(methods) > 0 {
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": fmt.Sprintf("%s %s 未在文档中定义", r.Method, r.URL.Path)})
+			return
+		}
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("未找到接口: %s %s", r.Method, r.URL.Path)})
+		return
+	}
+
+	plan, err := m.faultPlan(r, match)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "故障注入配置无效: " + err.Error()})
+		return
+	}
+	sleep(r.Context(), m.faults.delay(plan.latency))
+	if r.Context().Err() != nil {
+		return
+	}
+	if m.faults.chance(plan.resetRate) {
+		resetConnection(w)
+		return
+	}
+
+	if !m.cfg.DisableValidation {
+		if errs := validateRequest(m.spec, match, r); len(errs) > 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":      "请求参数校验失败",
+				"violations": errs,
+			})
+			return
+		}
+	}
+
+	prefer := parsePrefer(r.Header.Get("Prefer"))
+	code := prefer["code"]
+	injected := false
+	// 客户端通过 Prefer 指定了状态码时不再注入错误
+	if code == "" && m.faults.chance(plan.errorRate) {
+		codes := errorCodes(match.op, plan.errorCodes)
+		code = strconv.Itoa(codes[m.faults.pick(len(codes))])
+		injected = true
+	}
+	status, response := selectResponse(m.spec, match.op, code)
+	if prefer["code"] != "" {
+		w.Header().Set("Preference-Applied", "code="+prefer["code"])
+	}
+
+	header, body := buildMockResponse(r, m.spec, status, response, prefer["example"])
+	if injected {
+		w.Header().Set("X-Mock-Fault", "error")
+		if body == nil {
+			header.Set("Content-Type", "application/json")
+			body, _ = json.Marshal(map[string]string{"error": "模拟故障: " + http.StatusText(status)})
+		}
+	}
+	for k, v := range header {
+		w.Header()[k] = v
+	}
+	if len(body) > 0 && m.faults.chance(plan.truncateRate) {
+		writeTruncated(w, status, body)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// faultPlan 按优先级合并各层故障配置
+func (m *mockServer) faultPlan(r *http.Request, match *operationMatch) (faultPlan, error) {
+	var plan faultPlan
+	root, err := planFromValues(extensionGetter(m.spec))
+	if err != nil {
+		return plan, fmt.Errorf("文档 x-mock-%w", err)
+	}
+	plan.merge(root)
+	plan.merge(planFromConfig(m.cfg.Faults))
+
+	op, err := planFromValues(extensionGetter(match.op))
+	if err != nil {
+		return plan, fmt.Errorf("%s %s x-mock-%w", strings.ToUpper(match.Method), match.Path, err)
+	}
+	plan.merge(op)
+	for _, key := range []string{strings.ToUpper(match.Method) + " " + match.Path, getString(match.op, "operationId")} {
+		if f, ok := m.cfg.Operations[key]; ok && key != "" {
+			plan.merge(planFromConfig(f))
+			break
+		}
+	}
+
+	headers, err := planFromValues(headerGetter(r.Header))
+	if err != nil {
+		return plan, fmt.Errorf("X-Mock-%w", err)
+	}
+	plan.merge(headers)
+	return plan, nil
+}
+
+// parsePrefer 解析 Prefer 请求头（RFC 7240），如 "code=404, example=notFound"
+func parsePrefer(header string) map[string]string {
+	prefs := make(map[string]string)
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		prefs[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+	return prefs
+}
+
+// selectResponse 选择响应定义：指定状态码时依次匹配 "404"、"4XX"、"default"，
+// 否则选择最小的 2xx 状态码，再退回到 default
+func selectResponse(spec, op map[string]interface{}, code string) (int, map[string]interface{}) {
+	responses, _ := op["responses"].(map[string]interface{})
+	lookup := func(key string) map[string]interface{} {
+		resp, ok := responses[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if ref := getString(resp, "$ref"); ref != "" {
+			return resolveRef(spec, ref)
+		}
+		return resp
+	}
+
+	if code != "" {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			status = http.StatusOK
+		}
+		for _, key := range []string{code, code[:1] + "XX", strings.ToLower(code[:1]) + "xx", "default"} {
+			if resp := lookup(key); resp != nil {
+				return status, resp
+			}
+		}
+		return status, nil
+	}
+
+	codes := make([]string, 0, len(responses))
+	for key := range responses {
+		codes = append(codes, key)
+	}
+	sort.Strings(codes)
+	for _, key := range codes {
+		if strings.HasPrefix(key, "2") {
+			status, err := strconv.Atoi(key)
+			if err != nil {
+				status = http.StatusOK
+			}
+			return status, lookup(key)
+		}
+	}
+	return http.StatusOK, lookup("default")
+}
+
+// responseExample 返回媒体类型的示例：指定名称的 examples > example > 第一个 examples > schema 生成
+func responseExample(spec, media map[string]interface{}, name string) interface{} {
+	if examples, ok := media["examples"].(map[string]interface{}); ok && len(examples) > 0 {
+		value := func(key string) (interface{}, bool) {
+			ex, ok := examples[key].(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if ref := getString(ex, "$ref"); ref != "" {
+				ex = resolveRef(spec, ref)
+			}
+			v, ok := ex["value"]
+			return v, ok
+		}
+		if name != "" {
+			if v, ok := value(name); ok {
+				return v
+			}
+		}
+		if v, ok := media["example"]; ok {
+			return v
+		}
+		keys := make([]string, 0, len(examples))
+		for k := range examples {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if v, ok := value(keys[0]); ok {
+			return v
+		}
+	}
+	if v, ok := media["example"]; ok {
+		return v
+	}
+	schema, _ := media["schema"].(map[string]interface{})
+	return generateExample(spec, schema, 0)
+}
+
+// buildMockResponse 根据响应定义生成响应头与响应体，媒体类型优先按 Accept 请求头选择
+func buildMockResponse(r *http.Request, spec map[string]interface{}, status int, response map[string]interface{}, exampleName string) (http.Header, []byte) {
+	header := make(http.Header)
+	if headers, ok := response["headers"].(map[string]interface{}); ok {
+		for name, h := range headers {
+			def, _ := h.(map[string]interface{})
+			if ref := getString(def, "$ref"); ref != "" {
+				def = resolveRef(spec, ref)
+			}
+			schema, _ := def["schema"].(map[string]interface{})
+			if v := generateExample(spec, schema, 0); v != nil {
+				header.Set(name, fmt.Sprint(v))
+			}
+		}
+	}
+
+	content, _ := response["content"].(map[string]interface{})
+	if len(content) == 0 || status == http.StatusNoContent || status == http.StatusNotModified {
+		return header, nil
+	}
+
+	var mediaType string
+	var media map[string]interface{}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		accept = strings.TrimSpace(strings.Split(accept, ";")[0])
+		if accept == "" || accept == "*/*" {
+			continue
+		}
+		if mediaType, media = pickMediaType(content, accept); media != nil {
+			break
+		}
+	}
+	if media == nil {
+		mediaType, media = pickMediaType(content, "")
+	}
+	if mediaType == "*/*" {
+		mediaType = "application/json"
+	}
+
+	header.Set("Content-Type", mediaType)
+	example := responseExample(spec, media, exampleName)
+	if s, ok := example.(string); ok && !isJSONMediaType(mediaType) {
+		return header, []byte(s)
+	}
+	body, _ := json.Marshal(example)
+	return header, body
+}
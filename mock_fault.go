@@ -0,0 +1,404 @@
+package qingfeng
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyDistribution 模拟延迟的分布类型
+type LatencyDistribution string
+
+const (
+	// LatencyFixed 固定延迟（Min）
+	LatencyFixed LatencyDistribution = "fixed"
+	// LatencyUniform 在 [Min, Max] 之间均匀分布
+	LatencyUniform LatencyDistribution = "uniform"
+	// LatencyNormal 以 Mean 为均值、StdDev 为标准差的正态分布（截断到 [Min, Max]，Max 为 0 时不设上限）
+	LatencyNormal LatencyDistribution = "normal"
+)
+
+// MockLatency 模拟延迟配置，单次延迟最长 30 秒
+//
+// 在 x-mock-latency 扩展与 X-Mock-Latency 请求头中使用字符串形式：
+//   - "200ms"                 固定延迟
+//   - "100ms-500ms"           均匀分布
+//   - "normal(200ms,50ms)"    正态分布
+type MockLatency struct {
+	// Distribution 分布类型，默认 fixed
+	Distribution LatencyDistribution
+	// Min 最小延迟（fixed 时即为延迟值）
+	Min time.Duration
+	// Max 最大延迟
+	Max time.Duration
+	// Mean 正态分布均值
+	Mean time.Duration
+	// StdDev 正态分布标准差
+	StdDev time.Duration
+}
+
+// MockFault 故障注入配置，用于测试客户端的超时与重试逻辑
+// 各概率取值范围为 0-1，0 表示不注入
+type MockFault struct {
+	// Latency 响应前等待的时间
+	Latency *MockLatency
+	// ErrorRate 返回错误响应的概率，错误响应从文档中声明的 4xx/5xx 中随机选择
+	ErrorRate float64
+	// ErrorCodes 限定错误状态码，为空时使用文档中声明的全部 4xx/5xx（都没有时返回 500）
+	ErrorCodes []int
+	// ResetRate 不返回任何数据直接断开连接的概率
+	ResetRate float64
+	// TruncateRate 只返回部分响应体后断开连接的概率
+	TruncateRate float64
+	// Disable 先关闭优先级更低的配置（Faults 与文档中的 x-mock-* 扩展）中的延迟与故障，再应用本项设置的字段。
+	// 概率为 0 视为未设置，因此在 Operations 中为个别接口关闭全局故障时使用，如 {Disable: true}
+	Disable bool
+}
+
+// faultPlan 合并后的故障配置，nil 字段表示该层未设置
+type faultPlan struct {
+	latency      *MockLatency
+	errorRate    *float64
+	errorCodes   []int
+	resetRate    *float64
+	truncateRate *float64
+}
+
+// merge 用 other 中已设置的字段覆盖当前配置
+func (p *faultPlan) merge(other faultPlan) {
+	if other.latency != nil {
+		p.latency = other.latency
+	}
+	if other.errorRate != nil {
+		p.errorRate = other.errorRate
+	}
+	if other.errorCodes != nil {
+		p.errorCodes = other.errorCodes
+	}
+	if other.resetRate != nil {
+		p.resetRate = other.resetRate
+	}
+	if other.truncateRate != nil {
+		p.truncateRate = other.truncateRate
+	}
+}
+
+// planFromConfig 将 Config 中的故障配置转换为 faultPlan（零值视为未设置，Disable 时覆盖为 0）
+func planFromConfig(f MockFault) faultPlan {
+	var p faultPlan
+	if f.Disable {
+		var errorRate, resetRate, truncateRate float64
+		p.latency = &MockLatency{}
+		p.errorRate, p.resetRate, p.truncateRate = &errorRate, &resetRate, &truncateRate
+	}
+	if f.Latency != nil {
+		p.latency = f.Latency
+	}
+	if f.ErrorRate > 0 {
+		p.errorRate = &f.ErrorRate
+	}
+	if len(f.ErrorCodes) > 0 {
+		p.errorCodes = f.ErrorCodes
+	}
+	if f.ResetRate > 0 {
+		p.resetRate = &f.ResetRate
+	}
+	if f.TruncateRate > 0 {
+		p.truncateRate = &f.TruncateRate
+	}
+	return p
+}
+
+// planFromValues 从 x-mock-* 扩展或 X-Mock-* 请求头读取故障配置
+// get 返回指定名称（不含前缀，如 "latency"）的值，不存在时返回 nil
+func planFromValues(get func(name string) interface{}) (faultPlan, error) {
+	var p faultPlan
+	if v := get("latency"); v != nil {
+		latency, err := parseLatencyValue(v)
+		if err != nil {
+			return p, err
+		}
+		p.latency = latency
+	}
+	rates := map[string]**float64{"error-rate": &p.errorRate, "reset-rate": &p.resetRate, "truncate-rate": &p.truncateRate}
+	for name, target := range rates {
+		v := get(name)
+		if v == nil {
+			continue
+		}
+		rate, err := parseRate(v)
+		if err != nil {
+			return p, fmt.Errorf("%s: %w", name, err)
+		}
+		*target = &rate
+	}
+	if v := get("error-codes"); v != nil {
+		codes, err := parseCodes(v)
+		if err != nil {
+			return p, fmt.Errorf("error-codes: %w", err)
+		}
+		p.errorCodes = codes
+	}
+	return p, nil
+}
+
+// parseLatencyValue 解析延迟配置：数字（毫秒）或字符串形式
+func parseLatencyValue(v interface{}) (*MockLatency, error) {
+	switch val := v.(type) {
+	case float64:
+		return &MockLatency{Distribution: LatencyFixed, Min: time.Duration(val * float64(time.Millisecond))}, nil
+	case string:
+		return ParseLatency(val)
+	}
+	return nil, fmt.Errorf("latency: 不支持的类型 %T", v)
+}
+
+// ParseLatency 解析延迟字符串："200ms"、"100ms-500ms"、"normal(200ms,50ms)"
+func ParseLatency(s string) (*MockLatency, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "normal(") && strings.HasSuffix(s, ")") {
+		mean, stddev, ok := strings.Cut(s[len("normal("):len(s)-1], ",")
+		if !ok {
+			return nil, fmt.Errorf("latency: 正态分布格式应为 normal(均值,标准差): %q", s)
+		}
+		m, err := parseMillis(mean)
+		if err != nil {
+			return nil, err
+		}
+		d, err := parseMillis(stddev)
+		if err != nil {
+			return nil, err
+		}
+		return &MockLatency{Distribution: LatencyNormal, Mean: m, StdDev: d}, nil
+	}
+	if min, max, ok := strings.Cut(s, "-"); ok {
+		lo, err := parseMillis(min)
+		if err != nil {
+			return nil, err
+		}
+		hi, err := parseMillis(max)
+		if err != nil {
+			return nil, err
+		}
+		if hi < lo {
+			lo, hi = hi, lo
+		}
+		return &MockLatency{Distribution: LatencyUniform, Min: lo, Max: hi}, nil
+	}
+	d, err := parseMillis(s)
+	if err != nil {
+		return nil, err
+	}
+	return &MockLatency{Distribution: LatencyFixed, Min: d}, nil
+}
+
+// parseMillis 解析时长，纯数字按毫秒处理
+func parseMillis(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(n * float64(time.Millisecond)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("latency: 无效的时长 %q", s)
+	}
+	return d, nil
+}
+
+// parseRate 解析 0-1 之间的概率，支持 "0.2" 或 "20%"
+func parseRate(v interface{}) (float64, error) {
+	var rate float64
+	switch val := v.(type) {
+	case float64:
+		rate = val
+	case string:
+		val = strings.TrimSpace(val)
+		percent := strings.HasSuffix(val, "%")
+		n, err := strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的概率 %q", val)
+		}
+		if percent {
+			n /= 100
+		}
+		rate = n
+	default:
+		return 0, fmt.Errorf("不支持的类型 %T", v)
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("概率应在 0-1 之间: %v", rate)
+	}
+	return rate, nil
+}
+
+// parseCodes 解析状态码列表，支持数组或逗号分隔的字符串
+func parseCodes(v interface{}) ([]int, error) {
+	var parts []string
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			parts = append(parts, fmt.Sprint(item))
+		}
+	case float64:
+		parts = []string{strconv.Itoa(int(val))}
+	case string:
+		parts = strings.Split(val, ",")
+	default:
+		return nil, fmt.Errorf("不支持的类型 %T", v)
+	}
+	codes := make([]int, 0, len(parts))
+	for _, part := range parts {
+		code, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("无效的状态码 %q", part)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// faultInjector 按合并后的配置执行故障注入
+type faultInjector struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newFaultInjector(seed int64) *faultInjector {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &faultInjector{rand: rand.New(rand.NewSource(seed))}
+}
+
+// chance 以 rate 的概率返回 true
+func (f *faultInjector) chance(rate *float64) bool {
+	if rate == nil || *rate <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rand.Float64() < *rate
+}
+
+// pick 随机选择一个元素
+func (f *faultInjector) pick(n int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rand.Intn(n)
+}
+
+// maxMockLatency 单次模拟延迟的上限；X-Mock-Latency 由客户端任意设置，不设上限会长期占用处理协程
+const maxMockLatency = 30 * time.Second
+
+// delay 按分布计算本次延迟，不超过 maxMockLatency
+func (f *faultInjector) delay(l *MockLatency) time.Duration {
+	return min(f.sample(l), maxMockLatency)
+}
+
+// sample 按分布抽取延迟
+func (f *faultInjector) sample(l *MockLatency) time.Duration {
+	if l == nil {
+		return 0
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch l.Distribution {
+	case LatencyUniform:
+		if l.Max <= l.Min {
+			return l.Min
+		}
+		return l.Min + time.Duration(f.rand.Int63n(int64(l.Max-l.Min)))
+	case LatencyNormal:
+		d := time.Duration(float64(l.Mean) + f.rand.NormFloat64()*float64(l.StdDev))
+		if d < l.Min {
+			d = l.Min
+		}
+		if l.Max > 0 && d > l.Max {
+			d = l.Max
+		}
+		return d
+	}
+	return l.Min
+}
+
+// sleep 等待指定时长，客户端断开时提前返回
+func sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// errorCodes 返回可注入的错误状态码：配置值优先，否则为文档中声明的 4xx/5xx
+func errorCodes(op map[string]interface{}, configured []int) []int {
+	if len(configured) > 0 {
+		return configured
+	}
+	responses, _ := op["responses"].(map[string]interface{})
+	var codes []int
+	for key := range responses {
+		if code, err := strconv.Atoi(key); err == nil && code >= 400 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	if len(codes) == 0 {
+		codes = []int{http.StatusInternalServerError}
+	}
+	return codes
+}
+
+// resetConnection 直接断开连接：支持 Hijack 时发送 TCP RST，否则中止处理器
+func resetConnection(w http.ResponseWriter) {
+	if hj, ok := w.(http.Hijacker); ok {
+		if conn, _, err := hj.Hijack(); err == nil {
+			if tcp, ok := conn.(*net.TCPConn); ok {
+				tcp.SetLinger(0)
+			}
+			conn.Close()
+			return
+		}
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// writeTruncated 按完整长度声明 Content-Length，只写出一半响应体，
+// 服务端检测到长度不符会关闭连接，客户端读取时得到 unexpected EOF
+func writeTruncated(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	w.Write(body[:len(body)/2])
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	resetConnection(w)
+}
+
+// extensionGetter 返回读取 x-mock-* 扩展的函数
+func extensionGetter(obj map[string]interface{}) func(string) interface{} {
+	return func(name string) interface{} {
+		return obj["x-mock-"+name]
+	}
+}
+
+// headerGetter 返回读取 X-Mock-* 请求头的函数
+func headerGetter(h http.Header) func(string) interface{} {
+	return func(name string) interface{} {
+		if v := h.Get("X-Mock-" + name); v != "" {
+			return v
+		}
+		return nil
+	}
+}
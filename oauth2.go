@@ -0,0 +1,216 @@
+package qingfeng
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// OAuth2Config configures how the Authorize dialog obtains OAuth2 tokens
+// OAuth2 认证配置：认证对话框按文档中 OAuth2 安全方案的 flows（authorizationUrl、tokenUrl、refreshUrl、scopes）
+// 获取令牌，支持授权码（PKCE）、隐式、密码与客户端凭据模式，令牌过期前使用 refresh_token 自动刷新。
+//
+// 授权码与隐式模式的回调地址为 BasePath + "/oauth2-redirect"（如 http://localhost:8080/doc/oauth2-redirect），
+// 需要在授权服务器中登记。未配置 Config.OAuth2 时同样可以获取令牌，客户端 ID 等在对话框中填写
+type OAuth2Config struct {
+	// ClientID 对话框中默认填写的客户端 ID
+	ClientID string
+	// ClientSecret 默认填写的客户端密钥；会下发到浏览器，只应配置开发环境或测试用的客户端
+	ClientSecret string
+	// Scopes 默认勾选的权限范围，为空时勾选文档中声明的全部权限
+	Scopes []string
+	// AdditionalParams 授权请求附加的参数，如 {"audience": "https://api.example.com"}
+	AdditionalParams map[string]string
+	// RedirectURL 回调地址，默认为文档页面所在路径下的 oauth2-redirect（经反向代理改写路径时设置）
+	RedirectURL string
+	// ProxyTokenRequests 令牌请求经文档服务转发（BasePath + "/oauth2/token"），用于不支持跨域的令牌端点；
+	// 只转发到文档中声明的 tokenUrl、refreshUrl。需要为文档路由注册 POST 方法（RegisterRoutes 会自动注册）
+	ProxyTokenRequests bool
+}
+
+// oauth2RedirectPage 授权回调页面：将回调参数（查询参数与 # 片段）交给打开它的文档页面后关闭；
+// 打开者不可用时（如授权服务器设置了 Cross-Origin-Opener-Policy）经 localStorage 传递
+const oauth2RedirectPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>OAuth2 Redirect</title>
+</head>
+<body>
+<p>Authorization complete, you can close this window. 授权完成，可以关闭此窗口。</p>
+<script nonce="%s">
+(function () {
+    var params = {};
+    [location.search.slice(1), location.hash.slice(1)].forEach(function (part) {
+        new URLSearchParams(part).forEach(function (value, key) { params[key] = value; });
+    });
+    var message = { type: 'qingfeng-oauth2', params: params };
+    try {
+        if (window.opener && !window.opener.closed) {
+            window.opener.postMessage(message, location.origin);
+            window.close();
+            return;
+        }
+    } catch (e) {}
+    try {
+        localStorage.setItem('qingfeng_oauth2_callback', JSON.stringify(message));
+        window.close();
+    } catch (e) {}
+})();
+</script>
+</body>
+</html>
+`
+
+// serveOAuth2Redirect 返回授权回调页面；地址中带有授权码或令牌，不缓存且不发送 Referer
+func serveOAuth2Redirect(w http.ResponseWriter) {
+	nonce := newNonce()
+	h := w.Header()
+	if policy := h.Get("Content-Security-Policy"); policy != "" {
+		h.Set("Content-Security-Policy", addCSPNonce(policy, nonce))
+	}
+	h.Set("Content-Type", "text/html; charset=utf-8")
+	h.Set("Cache-Control", "no-store")
+	h.Set("Referrer-Policy", "no-referrer")
+	fmt.Fprintf(w, oauth2RedirectPage, nonce)
+}
+
+// oauth2Config 下发给界面的 OAuth2 配置，未配置时为 nil
+func oauth2Config(cfg *OAuth2Config) map[string]interface{} {
+	if cfg == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"clientId":         cfg.ClientID,
+		"clientSecret":     cfg.ClientSecret,
+		"scopes":           cfg.Scopes,
+		"additionalParams": cfg.AdditionalParams,
+		"redirectUrl":      cfg.RedirectURL,
+		"proxy":            cfg.ProxyTokenRequests,
+	}
+}
+
+// oauth2TokenURLs 返回文档 OAuth2 安全方案中声明的 tokenUrl 与 refreshUrl
+// （OpenAPI 3 components.securitySchemes 的 flows，Swagger 2.0 securityDefinitions 的 tokenUrl）
+func oauth2TokenURLs(spec map[string]interface{}) []string {
+	var urls []string
+	collect := func(schemes map[string]interface{}) {
+		for _, name := range sortedKeys(schemes) {
+			scheme, _ := schemes[name].(map[string]interface{})
+			if getString(scheme, "type") != "oauth2" {
+				continue
+			}
+			urls = append(urls, getString(scheme, "tokenUrl"))
+			flows, _ := scheme["flows"].(map[string]interface{})
+			for _, flowName := range sortedKeys(flows) {
+				flow, _ := flows[flowName].(map[string]interface{})
+				urls = append(urls, getString(flow, "tokenUrl"), getString(flow, "refreshUrl"))
+			}
+		}
+	}
+	components, _ := spec["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	collect(schemes)
+	definitions, _ := spec["securityDefinitions"].(map[string]interface{})
+	collect(definitions)
+
+	result := urls[:0]
+	for _, u := range urls {
+		if u != "" && !containsString(result, u) {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// oauth2TokenProxy 转发界面的令牌请求，只允许文档中声明的令牌端点，避免被用作任意地址的代理
+type oauth2TokenProxy struct {
+	client *http.Client
+
+	mu      sync.Mutex
+	src     []byte
+	allowed []string
+}
+
+const (
+	// maxTokenRequestSize 转发的令牌请求体的最大长度
+	maxTokenRequestSize = 64 << 10
+	// maxTokenResponseSize 令牌响应的最大长度，超出部分截断
+	maxTokenResponseSize = 1 << 20
+)
+
+func newOAuth2TokenProxy(cfg *OAuth2Config) *oauth2TokenProxy {
+	if cfg == nil || !cfg.ProxyTokenRequests {
+		return nil
+	}
+	return &oauth2TokenProxy{client: &http.Client{
+		Timeout: 30 * time.Second,
+		// 不跟随重定向，请求只发往文档中声明的地址
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}}
+}
+
+// allows 判断地址是否为文档中声明的令牌端点，文档不变时复用解析结果
+func (p *oauth2TokenProxy) allows(spec []byte, target string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.allowed == nil || !bytes.Equal(p.src, spec) {
+		p.src, p.allowed = spec, oauth2TokenURLs(parseSpec(spec))
+	}
+	return containsString(p.allowed, target)
+}
+
+// serve 处理 POST /oauth2/token?url=<令牌端点>：转发表单请求体与 Authorization 请求头，原样返回状态码与响应
+func (p *oauth2TokenProxy) serve(w http.ResponseWriter, r *http.Request, spec []byte) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method_not_allowed"})
+		return
+	}
+	target := r.URL.Query().Get("url")
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !p.allows(spec, target) {
+		writeJSON(w, http.StatusForbidden, map[string]string{
+			"error":             "invalid_request",
+			"error_description": "令牌端点未在文档的 OAuth2 安全方案中声明",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxTokenRequestSize+1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+	if len(body) > maxTokenRequestSize {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "invalid_request", "error_description": "令牌请求体过大"})
+		return
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "temporarily_unavailable", "error_description": err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, io.LimitReader(resp.Body, maxTokenResponseSize))
+}
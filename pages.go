@@ -0,0 +1,208 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// pagesExtension 导出的文档中保存页面的扩展字段，加载这样的文档时会还原页面
+const pagesExtension = "x-qingfeng-pages"
+
+// pageOrderPrefix 文件名开头用于排序的序号，如 01-quickstart.md
+var pageOrderPrefix = regexp.MustCompile(`^\d+[-_.]`)
+
+// PagesConfig configures Markdown guide pages shown above the API reference
+// 文档页面配置：加载目录中的 Markdown 文件（如接入指南、认证说明、错误码），显示在侧边栏接口分组之上。
+//
+// 页面按文件路径排序，文件名开头的序号（01-、02_）只用于排序；标题取第一个一级标题，没有时使用文件名。
+// 页面中的链接：
+//
+//	[创建用户](op:createUser)        按 operationId 跳转到接口
+//	[创建用户](op:POST /users)       按方法与路径跳转到接口
+//	[认证](auth.md#token)            跳转到其他页面（也可写作 page:auth#token）
+//	![流程](images/flow.png)          页面目录中的图片，经 /pages/... 提供
+//
+// 原始 HTML 会被转义，不会执行
+type PagesConfig struct {
+	// Dir Markdown 文件所在目录（包含子目录）
+	Dir string
+	// FS Markdown 文件所在的文件系统（如 embed.FS），优先于 Dir
+	FS fs.FS
+	// Title 侧边栏分组名称，默认为“指南”（随界面语言变化）
+	Title string
+}
+
+// guidePage 一个文档页面
+type guidePage struct {
+	Slug     string `json:"slug"`
+	Title    string `json:"title"`
+	HTML     string `json:"html"`
+	Markdown string `json:"markdown"`
+	// Text 纯文本内容，用于界面的本地过滤
+	Text     string `json:"text"`
+	headings []string
+}
+
+// guidePages 加载的全部文档页面
+type guidePages struct {
+	title string
+	// fsys 页面目录，提供页面引用的图片等文件；页面来自文档扩展字段时为 nil
+	fsys  fs.FS
+	pages []*guidePage
+	json  []byte
+	index *searchIndex
+}
+
+// pageSource 页面的原始内容
+type pageSource struct {
+	file     string
+	slug     string
+	title    string
+	markdown string
+}
+
+// loadPages 加载目录中的所有 Markdown 文件（跳过以 . 开头的目录）
+func loadPages(cfg *PagesConfig) (*guidePages, error) {
+	fsys := cfg.FS
+	if fsys == nil {
+		if cfg.Dir == "" {
+			return nil, fmt.Errorf("未配置 Pages.Dir 或 Pages.FS")
+		}
+		fsys = os.DirFS(cfg.Dir)
+	}
+	var sources []pageSource
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(path.Ext(name), ".md") {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, pageSource{file: name, markdown: string(data)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newGuidePages(cfg.Title, fsys, sources), nil
+}
+
+// pagesFromSpec 还原导出到文档 x-qingfeng-pages 字段中的页面，没有页面时返回 nil
+func pagesFromSpec(spec []byte) *guidePages {
+	doc := parseSpec(spec)
+	ext, _ := doc[pagesExtension].(map[string]interface{})
+	items, _ := ext["pages"].([]interface{})
+	var sources []pageSource
+	for _, item := range items {
+		page, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		slug := getString(page, "slug")
+		if slug == "" {
+			continue
+		}
+		sources = append(sources, pageSource{
+			file:     slug + ".md",
+			slug:     slug,
+			title:    getString(page, "title"),
+			markdown: getString(page, "markdown"),
+		})
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return newGuidePages(getString(ext, "title"), nil, sources)
+}
+
+// newGuidePages 渲染页面并建立搜索索引
+func newGuidePages(title string, fsys fs.FS, sources []pageSource) *guidePages {
+	slugs := make(map[string]string, len(sources))
+	used := make(map[string]bool, len(sources))
+	for i := range sources {
+		slug := sources[i].slug
+		if slug == "" {
+			slug = pageSlug(sources[i].file)
+		}
+		for base, n := slug, 2; used[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[slug] = true
+		sources[i].slug = slug
+		slugs[sources[i].file] = slug
+	}
+
+	g := &guidePages{title: title, fsys: fsys}
+	for _, src := range sources {
+		r := &markdownRenderer{dir: path.Dir(src.file), slugs: slugs}
+		content := r.render(src.markdown)
+		page := &guidePage{
+			Slug:     src.slug,
+			Title:    src.title,
+			HTML:     content,
+			Markdown: src.markdown,
+			Text:     plainText(content),
+			headings: r.headings,
+		}
+		if page.Title == "" {
+			page.Title = r.title
+		}
+		if page.Title == "" {
+			page.Title = strings.NewReplacer("-", " ", "_", " ").Replace(path.Base(src.slug))
+		}
+		g.pages = append(g.pages, page)
+	}
+	g.index = buildPageSearchIndex(g.pages)
+	g.json, _ = json.Marshal(map[string]interface{}{"title": title, "pages": g.pages})
+	return g
+}
+
+// pageSlug 根据文件路径生成页面 slug：去掉扩展名与各级名称开头的序号，空格转为 -
+func pageSlug(file string) string {
+	parts := strings.Split(strings.TrimSuffix(file, path.Ext(file)), "/")
+	for i, part := range parts {
+		if trimmed := pageOrderPrefix.ReplaceAllString(part, ""); trimmed != "" {
+			part = trimmed
+		}
+		parts[i] = strings.ReplaceAll(part, " ", "-")
+	}
+	return strings.Join(parts, "/")
+}
+
+// serveFile 提供页面目录中的图片与 PDF 等文件，其他类型的文件（包括 Markdown 源文件）不对外提供
+func (g *guidePages) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	contentType := mime.TypeByExtension(path.Ext(name))
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if !fs.ValidPath(name) || !(strings.HasPrefix(mediaType, "image/") || mediaType == "application/pdf") {
+		http.NotFound(w, r)
+		return
+	}
+	data, err := fs.ReadFile(g.fsys, name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if mediaType == "image/svg+xml" {
+		// SVG 可包含脚本，直接打开时禁止执行
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	}
+	newCachedBody(contentType, data).write(w, r, "no-cache")
+}
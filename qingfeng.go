@@ -10,20 +10,29 @@
 package qingfeng
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
+	"io"
 	"io/fs"
+	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/buyfakett/qingfeng/lint"
 	"github.com/gin-gonic/gin"
 )
 
 // Version is the current version of QingFeng
 const Version = "1.7.0"
 
-//go:embed ui/default/* ui/minimal/* ui/modern/* ui/assets/css/* ui/assets/webfonts/*
+//go:generate go run ./internal/precompress ui
+
+//go:embed ui/default/* ui/minimal/* ui/modern/* ui/assets/css/* ui/assets/webfonts/* ui/i18n/*
 var uiFS embed.FS
 
 // UITheme represents available UI themes
@@ -72,7 +81,7 @@ type Config struct {
 	// BasePath prefix for the documentation routes (文档路由前缀，默认 /doc)
 	BasePath string
 	// DocPath is the path to swagger.json/openapi.json file (文档文件路径)
-	// 支持 Swagger 2.0 和 OpenAPI 3.0 格式
+	// 支持 Swagger 2.0 和 OpenAPI 3.0 格式，支持 YAML 及引用其他文件或 URL 的 $ref（加载时打包为单个文档）
 	DocPath string
 	// DocJSON allows passing swagger spec directly as JSON bytes (直接传入文档 JSON)
 	DocJSON []byte
@@ -98,8 +107,38 @@ type Config struct {
 	// SwagArgs is deprecated, use AutoGenerate instead
 	// Deprecated: 已废弃，内置生成器不需要额外参数
 	SwagArgs []string
-	// UITheme selects the UI theme: "default", "minimal", "modern" (UI 主题选择)
+	// UITheme selects the UI theme: "default", "minimal", "modern" or a custom theme name (UI 主题选择)
 	UITheme UITheme
+	// CustomThemes adds themes served from the given file systems, see RegisterTheme
+	// 自定义主题：名称 → 主题文件（结构与内置主题相同，缺少的文件使用内置主题的文件），详见 RegisterTheme
+	CustomThemes map[string]fs.FS
+	// CustomCSS is inline CSS appended to the page head, e.g. CSS variables for branding
+	// 自定义 CSS，注入到页面 <head> 末尾（晚于主题样式），可覆盖 --primary 等 CSS 变量
+	CustomCSS string
+	// CustomJS is an inline script run after app.js, e.g. analytics or a banner
+	// 自定义脚本，在 app.js 之后执行（如统计代码、公告横幅）
+	CustomJS string
+	// HeadHTML is raw HTML appended to the page head; its <script>/<style> tags get the CSP nonce
+	// 追加到 <head> 末尾的 HTML（如 meta 标签、外部统计脚本），其中的 <script>、<style> 会自动加上 CSP nonce
+	HeadHTML string
+	// Language sets the UI language, e.g. "zh-CN" or "en"; empty negotiates it from Accept-Language
+	// 界面语言（如 "zh-CN"、"en"）；为空时按浏览器的 Accept-Language 选择，没有匹配的语言时使用简体中文。
+	// 用户在页面中切换的语言优先于该设置；文档中有 x-i18n 等翻译时，同样决定文档内容的语言
+	Language string
+	// Locales adds or overrides UI language bundles: language code → message key → text
+	// 自定义界面语言包：语言代码 → 文案 key → 文本（key 见 ui/i18n/zh-CN.json）；
+	// 与内置语言同名时覆盖其中的文案，缺少的文案使用简体中文
+	Locales map[string]map[string]string
+	// Pages loads Markdown guides (onboarding, authentication, error codes) shown above the API reference
+	// 文档页面：加载 Markdown 指南并显示在侧边栏接口分组之上，可链接到接口，支持搜索与随文档导出，nil 表示不启用
+	Pages *PagesConfig
+	// OAuth2 sets defaults for obtaining OAuth2 tokens (client ID, scopes, token proxy) in the Authorize dialog
+	// OAuth2 认证配置：对话框中默认的客户端 ID、权限范围，以及是否经文档服务转发令牌请求；
+	// 授权流程按文档中的 OAuth2 安全方案进行，nil 时同样可用
+	OAuth2 *OAuth2Config
+	// Security configures CORS and security headers (CSP, X-Frame-Options, Referrer-Policy)
+	// 安全配置：文档接口的跨域策略与安全响应头，nil 时文档接口允许任意来源跨域读取
+	Security *SecurityConfig
 	// Logo is the URL or base64 of custom logo image (自定义 Logo)
 	Logo string
 	// LogoLink is the URL to navigate when clicking the logo (Logo 点击跳转链接)
@@ -107,6 +146,32 @@ type Config struct {
 	// Environments is a list of environment configurations for switching baseUrl
 	// 环境配置列表，用于切换不同环境的 baseUrl
 	Environments []Environment
+	// Mock enables a mock API generated from the spec under BasePath + Mock.Prefix
+	// 启用基于文档的模拟接口（默认挂载在 /doc/mock/*），nil 表示不启用
+	// 注意：模拟接口需要接收所有 HTTP 方法，Gin 请使用 r.Any("/doc/*any", ...)
+	Mock *MockConfig
+	// Routes returns the application's registered routes for the /coverage report
+	// 返回应用注册的路由列表，用于 /coverage 覆盖率报告（请求时调用，文档自身的路由会被忽略）
+	// 例如 Gin: func() []qingfeng.Route { return qingfeng.GinRoutes(r.Routes()) }
+	Routes func() []Route
+	// Introspect builds a baseline document from Routes for handlers without swag annotations
+	// 根据 Routes 自动补充未添加注释的接口（注释生成的接口优先），文档在首次请求时生成
+	Introspect bool
+	// Capture merges traffic recorded by a Capture middleware into the served spec as observed examples
+	// 流量采集器，采集到的真实请求/响应会作为示例合并进文档（建议仅在开发环境使用）
+	Capture *Capture
+	// Lint configures the rules used by the /lint report, nil uses the built-in defaults
+	// /lint 文档质量报告的规则配置，nil 使用内置默认规则
+	Lint *lint.Config
+	// History records every newly generated spec and serves /changelog between versions
+	// 文档历史：记录每次生成的新文档，提供 /changelog 版本对比，并在侧边栏标记新增/变更的接口
+	History *SpecHistory
+	// Aggregate merges specs of upstream services (files or URLs) into the served document
+	// 聚合多个上游服务的文档（本地文件或 URL），按路径前缀与标签命名空间合并为一个文档
+	Aggregate *AggregateConfig
+	// LazyLoad makes the UI load a lightweight index first and fetch each operation on selection
+	// 按需加载：UI 先加载只含摘要的索引（/index.json），选中接口时再获取详情（/operation），适合超大文档
+	LazyLoad bool
 }
 
 // DefaultConfig returns a default configuration
@@ -134,7 +199,14 @@ func Handler(cfg Config) gin.HandlerFunc {
 // RegisterRoutes registers QingFeng routes to a Gin router group
 func RegisterRoutes(router *gin.RouterGroup, cfg Config) {
 	handler := Handler(cfg)
+	if cfg.Mock != nil {
+		// 模拟接口需要支持所有 HTTP 方法
+		router.Any("/*filepath", handler)
+		return
+	}
 	router.GET("/*filepath", handler)
+	// 粘贴 cURL 与 OAuth2 令牌转发使用 POST
+	router.POST("/*filepath", handler)
 }
 
 // HTTPHandler returns a standard http.Handler for use with any Go web framework
@@ -155,8 +227,10 @@ func HTTPHandler(cfg Config) http.Handler {
 	if cfg.DocJSON != nil {
 		specJSON = cfg.DocJSON
 	} else if cfg.DocPath != "" {
-		if data, err := os.ReadFile(cfg.DocPath); err == nil {
+		if data, err := BundleSpec(cfg.DocPath); err == nil {
 			specJSON = data
+		} else if _, statErr := os.Stat(cfg.DocPath); statErr == nil {
+			log.Printf("[QingFeng] 打包文档 %s 失败: %v\n", cfg.DocPath, err)
 		}
 	}
 
@@ -167,22 +241,128 @@ func HTTPHandler(cfg Config) http.Handler {
 		}
 	}
 
-	// Prepare file servers for each theme
-	defaultFS, _ := fs.Sub(uiFS, "ui/default")
-	minimalFS, _ := fs.Sub(uiFS, "ui/minimal")
-	modernFS, _ := fs.Sub(uiFS, "ui/modern")
-	assetsFS, _ := fs.Sub(uiFS, "ui/assets")
+	// 模拟接口
+	var mockHandler http.Handler
+	mockPrefix := ""
+	if cfg.Mock != nil {
+		mockPrefix = "/" + strings.Trim(cfg.Mock.Prefix, "/")
+		if mockPrefix == "/" {
+			mockPrefix = "/mock"
+		}
+	}
+
+	// 路由注册与 Op 声明通常晚于文档处理器创建，相关文档在首次请求时生成
+	var specOnce sync.Once
+	var agg *aggregator
+	var localized specLocalizer
+	var variants specVariants
+	// 粘贴 cURL 时匹配接口用的路由表，文档不变时复用
+	var curlRoutes routeCache
+
+	// 文档页面：优先使用 Pages 配置，未配置时还原文档中导出的页面
+	var guides *guidePages
+	if cfg.Pages != nil {
+		if pages, err := loadPages(cfg.Pages); err == nil {
+			guides = pages
+		} else {
+			log.Printf("[QingFeng] 加载文档页面失败: %v\n", err)
+		}
+	}
+
+	// withCaptures 合并流量采集到的示例
+	withCaptures := func(spec []byte) []byte {
+		if cfg.Capture != nil && spec != nil {
+			if merged, err := cfg.Capture.Merge(spec); err == nil {
+				return merged
+			}
+		}
+		return spec
+	}
+	// localize 返回请求语言的文档：?lang= 优先，其次为配置的界面语言，最后按 Accept-Language 协商；
+	// 文档中没有对应翻译时返回原文档，语言为空字符串
+	localize := func(w http.ResponseWriter, r *http.Request, spec []byte) ([]byte, string) {
+		available := localized.available(spec)
+		if len(available) == 0 {
+			return spec, ""
+		}
+		tag := r.URL.Query().Get("lang")
+		if tag == "" {
+			tag = cfg.Language
+		}
+		lang := matchLanguage(tag, available)
+		if tag == "" {
+			w.Header().Add("Vary", "Accept-Language")
+			lang = negotiateLanguage(r.Header.Get("Accept-Language"), available)
+		}
+		return localized.get(spec, lang), lang
+	}
+	loadSpec := func() []byte {
+		specOnce.Do(func() {
+			// 文档合并顺序：注释生成 < 代码声明（Op）< 路由自省补充未声明的接口
+			if len(registeredOperations()) > 0 {
+				if data, err := MergeOperations(cfg, specJSON); err == nil {
+					specJSON = data
+				} else {
+					log.Printf("[QingFeng] 合并代码声明的接口失败: %v\n", err)
+				}
+			}
+			if cfg.Introspect && cfg.Routes != nil {
+				if data, err := IntrospectSpec(cfg, cfg.Routes(), specJSON); err == nil {
+					specJSON = data
+				} else {
+					log.Printf("[QingFeng] 根据路由生成文档失败: %v\n", err)
+				}
+			}
+			// 聚合上游文档；定时刷新只更新文档接口，模拟接口与流量采集使用首次聚合的结果
+			if cfg.Aggregate != nil {
+				agg = newAggregator(cfg, specJSON)
+				agg.refresh(context.Background())
+				specJSON = agg.spec()
+				if cfg.History != nil {
+					agg.onChange = func(spec []byte) {
+						if _, err := cfg.History.Record(spec); err != nil {
+							log.Printf("[QingFeng] 记录文档历史失败: %v\n", err)
+						}
+					}
+				}
+				agg.start()
+			}
+			if cfg.Mock != nil {
+				mockHandler = newMockServer(specJSON, *cfg.Mock, cfg.Security)
+			}
+			if cfg.Capture != nil && specJSON != nil {
+				cfg.Capture.setSpec(specJSON)
+			}
+			if cfg.History != nil && specJSON != nil {
+				if _, err := cfg.History.Record(specJSON); err != nil {
+					log.Printf("[QingFeng] 记录文档历史失败: %v\n", err)
+				}
+			}
+			if guides == nil && cfg.Pages == nil && specJSON != nil {
+				guides = pagesFromSpec(specJSON)
+			}
+			// 在后台建立搜索索引，避免首次搜索等待
+			if specJSON != nil {
+				go variants.get("").searches.get(specJSON)
+			}
+		})
+		return specJSON
+	}
 
-	fileServers := map[string]http.Handler{
-		"default": http.FileServer(http.FS(defaultFS)),
-		"minimal": http.FileServer(http.FS(minimalFS)),
-		"modern":  http.FileServer(http.FS(modernFS)),
+	// Prepare static files: assets are served under a content-hashed path with immutable caching
+	// 静态资源以内容哈希作为 URL 版本号（/assets/<hash>/css/...），可长期缓存
+	themes, themeNames := loadThemes(customThemes(cfg))
+	injector := newPageInjector(cfg)
+	security := newSecurityPolicy(cfg)
+	tokenProxy := newOAuth2TokenProxy(cfg.OAuth2)
+	versionedAssets := make(map[string]map[string]*cachedBody)
+	for _, t := range themes {
+		versionedAssets[t.assetVersion] = t.assets
 	}
-	assetsServer := http.FileServer(http.FS(assetsFS))
 
 	// Default theme from config
 	defaultTheme := string(cfg.UITheme)
-	if defaultTheme == "" {
+	if _, ok := themes[defaultTheme]; !ok {
 		defaultTheme = "default"
 	}
 
@@ -192,22 +372,39 @@ func HTTPHandler(cfg Config) http.Handler {
 		persistParams = *cfg.PersistParams
 	}
 
-	// Prepare config JSON for frontend
-	configJSON, _ := json.Marshal(map[string]interface{}{
-		"title":           cfg.Title,
-		"description":     cfg.Description,
-		"version":         cfg.Version,
-		"enableDebug":     cfg.EnableDebug,
-		"darkMode":        cfg.DarkMode,
-		"globalHeaders":   cfg.GlobalHeaders,
-		"defaultTheme":    defaultTheme,
-		"themes":          []string{"default", "minimal", "modern"},
-		"qingfengVersion": Version,
-		"logo":            cfg.Logo,
-		"logoLink":        cfg.LogoLink,
-		"environments":    cfg.Environments,
-		"persistParams":   persistParams,
-	})
+	// UI language bundles
+	// 界面语言包：未指定语言时按 Accept-Language 协商
+	i18n := loadLocales(cfg.Locales)
+	language := i18n.match(cfg.Language)
+	if cfg.Language != "" && language == "" {
+		log.Printf("[QingFeng] 未找到界面语言 %s，将按 Accept-Language 选择\n", cfg.Language)
+	}
+
+	// Prepare config JSON for frontend (one per language)
+	configJSONs := make(map[string][]byte, len(i18n.languages))
+	for _, lang := range i18n.languages {
+		configJSONs[lang.Code], _ = json.Marshal(map[string]interface{}{
+			"title":           cfg.Title,
+			"description":     cfg.Description,
+			"version":         cfg.Version,
+			"enableDebug":     cfg.EnableDebug,
+			"darkMode":        cfg.DarkMode,
+			"globalHeaders":   cfg.GlobalHeaders,
+			"defaultTheme":    defaultTheme,
+			"themes":          themeNames,
+			"qingfengVersion": Version,
+			"logo":            cfg.Logo,
+			"logoLink":        cfg.LogoLink,
+			"environments":    cfg.Environments,
+			"persistParams":   persistParams,
+			"mockBaseUrl":     mockBaseURL(cfg.BasePath, mockPrefix),
+			"history":         cfg.History != nil,
+			"lazyLoad":        cfg.LazyLoad,
+			"oauth2":          oauth2Config(cfg.OAuth2),
+			"language":        lang.Code,
+			"languages":       i18n.languages,
+		})
+	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
@@ -220,55 +417,350 @@ func HTTPHandler(cfg Config) http.Handler {
 			path = "/"
 		}
 
+		spec := loadSpec()
+		if agg != nil {
+			spec = agg.spec()
+		}
+
+		// 安全响应头与跨域策略；模拟接口的跨域由模拟服务按 MockConfig（未配置时沿用 Security）的来源处理
+		if security != nil {
+			security.setHeaders(w, spec)
+			if corsRoutes[path] && security.handleCORS(w, r) {
+				return
+			}
+		}
+
+		// 模拟接口
+		if mockHandler != nil && (path == mockPrefix || strings.HasPrefix(path, mockPrefix+"/")) {
+			r.URL.Path = strings.TrimPrefix(path, mockPrefix)
+			mockHandler.ServeHTTP(w, r)
+			return
+		}
+
 		// Get theme from query parameter or use default
 		theme := r.URL.Query().Get("theme")
 		if theme == "" {
 			theme = defaultTheme
 		}
-		if _, ok := fileServers[theme]; !ok {
+		if _, ok := themes[theme]; !ok {
 			theme = defaultTheme
 		}
 
-		// Serve swagger.json / openapi.json
+		// Serve swagger.json / openapi.json（?deref=1 返回展开所有 $ref 的文档）
 		if path == "/swagger.json" || path == "/openapi.json" || path == "/api-docs" || path == "/doc.json" {
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			if specJSON != nil {
-				w.Write(specJSON)
+			if security == nil {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			}
+			if spec != nil {
+				spec, lang := localize(w, r, spec)
+				spec = withCaptures(spec)
+				if deref := r.URL.Query().Get("deref"); deref == "1" || deref == "true" {
+					if expanded, err := variants.get(lang).deref.get(spec); err == nil {
+						spec = expanded
+					}
+				}
+				writeCacheable(w, r, "application/json", spec)
 				return
 			}
 			// 尝试从文件读取
 			if cfg.DocPath != "" {
 				if data, err := os.ReadFile(cfg.DocPath); err == nil {
-					w.Write(data)
+					writeCacheable(w, r, "application/json", data)
 					return
 				}
 			}
+			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
 			w.Write([]byte(`{"error": "API documentation not found. Enable AutoGenerate or provide DocPath/DocJSON."}`))
 			return
 		}
 
-		// Serve config
+		// 按需加载：文档索引与单个接口详情（带 ETag，浏览器可缓存）
+		if path == "/index.json" || path == "/operation" {
+			if spec == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "API documentation not found"})
+				return
+			}
+			spec, lang := localize(w, r, spec)
+			operations := &variants.get(lang).operations
+			var data []byte
+			var err error
+			found := true
+			if path == "/index.json" {
+				data, err = operations.indexJSON(withCaptures(spec))
+			} else {
+				query := r.URL.Query()
+				data, found, err = operations.operationJSON(withCaptures(spec), query.Get("method"), query.Get("path"))
+			}
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			if !found {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "接口不存在"})
+				return
+			}
+			writeCacheable(w, r, "application/json", data)
+			return
+		}
+
+		// 全文搜索：?q=user method:post tag:User deprecated:false&limit=50
+		if path == "/search" {
+			if spec == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "API documentation not found"})
+				return
+			}
+			spec, lang := localize(w, r, spec)
+			index, err := variants.get(lang).searches.get(spec)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			query := r.URL.Query()
+			limit, err := strconv.Atoi(query.Get("limit"))
+			if err != nil || limit <= 0 {
+				limit = 50
+			}
+			if limit > 200 {
+				limit = 200
+			}
+			results, total := index.search(query.Get("q"), limit)
+			if guides != nil {
+				pageResults, pageTotal := guides.index.search(query.Get("q"), limit)
+				results, total = mergeSearchResults(results, pageResults, limit), total+pageTotal
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"query":   query.Get("q"),
+				"total":   total,
+				"results": results,
+			})
+			return
+		}
+
+		// 文档页面与页面引用的图片等文件
+		if path == "/pages.json" {
+			if guides == nil {
+				// 未配置页面时返回空列表，界面无需区分
+				writeCacheable(w, r, "application/json", []byte(`{"title":"","pages":[]}`))
+				return
+			}
+			writeCacheable(w, r, "application/json", guides.json)
+			return
+		}
+		if name, ok := strings.CutPrefix(path, "/pages/"); ok && guides != nil && guides.fsys != nil {
+			guides.serveFile(w, r, name)
+			return
+		}
+
+		// OAuth2 授权回调页面（/oauth2-redirect.html 兼容 Swagger UI 的回调地址）与令牌转发
+		if path == "/oauth2-redirect" || path == "/oauth2-redirect.html" {
+			serveOAuth2Redirect(w)
+			return
+		}
+		if path == "/oauth2/token" && tokenProxy != nil {
+			tokenProxy.serve(w, r, spec)
+			return
+		}
+
+		// 下载打包后的单文件文档
+		if path == "/bundle.json" {
+			if spec == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "API documentation not found"})
+				return
+			}
+			spec, _ := localize(w, r, spec)
+			w.Header().Set("Content-Disposition", `attachment; filename="bundle.json"`)
+			writeCacheable(w, r, "application/json", withCaptures(spec))
+			return
+		}
+
+		// 解析 cURL 命令并匹配文档中的接口（POST 请求体；GET ?cmd= 仅为兼容保留，命令会出现在访问日志中）
+		if path == "/curl" {
+			command := r.URL.Query().Get("cmd")
+			if r.Method == http.MethodPost {
+				data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+				if err != nil {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+					return
+				}
+				command = string(data)
+			}
+			cmd, err := ParseCurl(command)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			doc, routes := curlRoutes.get(spec)
+			var prefixes []string
+			for _, env := range cfg.Environments {
+				if u, err := url.Parse(env.BaseURL); err == nil {
+					prefixes = append(prefixes, u.Path)
+				}
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"request":   cmd,
+				"operation": findOperation(doc, routes, cmd.Method, cmd.Path, prefixes),
+			})
+			return
+		}
+
+		// 路由覆盖率报告
+		if path == "/coverage" {
+			if cfg.Routes == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "未配置 Routes，无法生成覆盖率报告"})
+				return
+			}
+			writeJSON(w, http.StatusOK, Coverage(spec, cfg.Routes(), CoverageOptions{Ignore: []string{cfg.BasePath}}))
+			return
+		}
+
+		// 文档质量报告（?format=json|text|sarif，默认 json）
+		if path == "/lint" {
+			if spec == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "API documentation not found"})
+				return
+			}
+			var lintCfg lint.Config
+			if cfg.Lint != nil {
+				lintCfg = *cfg.Lint
+			}
+			report, err := lint.Run(spec, lintCfg)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			format := r.URL.Query().Get("format")
+			switch format {
+			case "", "json":
+				writeJSON(w, http.StatusOK, report)
+			case "sarif":
+				w.Header().Set("Content-Type", "application/sarif+json")
+				report.WriteSARIF(w, cfg.BasePath+"/openapi.json")
+			case "text":
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				report.WriteText(w, "openapi.json")
+			default:
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "不支持的输出格式: " + format})
+			}
+			return
+		}
+
+		// 上游文档拉取状态
+		if path == "/aggregate" {
+			if agg == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "未配置 Aggregate"})
+				return
+			}
+			writeJSON(w, http.StatusOK, agg.status())
+			return
+		}
+
+		// 版本变更日志（?from=&to= 为哈希或 info.version，?format=markdown 输出 Markdown）
+		if path == "/changelog" {
+			if cfg.History == nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "未配置 History，无法生成变更日志"})
+				return
+			}
+			versions, err := cfg.History.Versions()
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			query := r.URL.Query()
+			changelog, err := cfg.History.Changelog(query.Get("from"), query.Get("to"))
+			if query.Get("format") == "markdown" {
+				w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+				if err != nil {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(err.Error() + "\n"))
+					return
+				}
+				w.Write([]byte(changelog.Diff.Markdown()))
+				return
+			}
+			result := map[string]interface{}{"versions": versions, "changelog": changelog}
+			if err != nil {
+				result["error"] = err.Error()
+			}
+			writeJSON(w, http.StatusOK, result)
+			return
+		}
+
+		// Serve UI language bundles (/i18n/{lang}.json)
+		if name, ok := strings.CutPrefix(path, "/i18n/"); ok {
+			if lang := i18n.match(strings.TrimSuffix(name, ".json")); lang != "" {
+				writeCacheable(w, r, "application/json", i18n.bundles[lang])
+				return
+			}
+			http.NotFound(w, r)
+			return
+		}
+
+		// Serve config：界面语言为配置的语言，未配置时按 Accept-Language 协商
 		if path == "/config.json" {
-			w.Header().Set("Content-Type", "application/json")
-			w.Write(configJSON)
+			lang := language
+			if lang == "" {
+				w.Header().Add("Vary", "Accept-Language")
+				lang = i18n.negotiate(r.Header.Get("Accept-Language"))
+			}
+			if lang == "" {
+				lang = defaultLanguage
+			}
+			writeCacheable(w, r, "application/json", configJSONs[lang])
 			return
 		}
 
-		// Serve assets (CSS, fonts)
-		if strings.HasPrefix(path, "/assets/") {
-			r.URL.Path = strings.TrimPrefix(path, "/assets")
-			assetsServer.ServeHTTP(w, r)
+		// Serve assets (CSS, fonts)：带版本号的 URL 长期缓存，不带版本号的每次重新验证
+		if name, ok := strings.CutPrefix(path, "/assets/"); ok {
+			assets, cacheControl := themes[theme].assets, "no-cache"
+			if version, rest, ok := strings.Cut(name, "/"); ok && versionedAssets[version] != nil {
+				assets, name, cacheControl = versionedAssets[version], rest, immutableCacheControl
+			}
+			if file, ok := assets[name]; ok {
+				file.write(w, r, cacheControl)
+				return
+			}
+			http.NotFound(w, r)
 			return
 		}
 
 		// Serve static files using selected theme
-		r.URL.Path = path
-		fileServers[theme].ServeHTTP(w, r)
+		name := strings.TrimPrefix(path, "/")
+		if name == "" {
+			name = "index.html"
+		}
+		if file, ok := themes[theme].files[name]; ok {
+			// 注入自定义内容的页面每次请求使用新的 CSP nonce，不能缓存
+			if name == "index.html" && injector != nil {
+				nonce := newNonce()
+				if policy := w.Header().Get("Content-Security-Policy"); policy != "" {
+					w.Header().Set("Content-Security-Policy", addCSPNonce(policy, nonce))
+				}
+				newCachedBody(file.contentType, injector.render(file.body, nonce)).write(w, r, "no-store")
+				return
+			}
+			file.write(w, r, "no-cache")
+			return
+		}
+		// 带内容哈希的脚本不携带 theme 参数，按文件名查找所属主题
+		for _, t := range themes {
+			if name == t.scriptName {
+				t.files["app.js"].write(w, r, immutableCacheControl)
+				return
+			}
+		}
+		http.NotFound(w, r)
 	})
 }
 
+// mockBaseURL 返回模拟接口的完整路径前缀，未启用时返回空字符串
+func mockBaseURL(basePath, mockPrefix string) string {
+	if mockPrefix == "" {
+		return ""
+	}
+	return strings.TrimSuffix(basePath, "/") + mockPrefix
+}
+
 // truncateString 截断字符串（按字符而非字节，正确处理中文）
 func truncateString(s string, maxLen int) string {
 	r := []rune(s)
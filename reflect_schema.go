@@ -0,0 +1,293 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaBuilder 通过反射将 Go 类型转换为 JSON Schema，结构体统一放入 components/schemas 并以 $ref 引用
+type schemaBuilder struct {
+	schemas map[string]interface{}  // components/schemas
+	names   map[reflect.Type]string // 已生成的结构体 -> schema 名称
+}
+
+func newSchemaBuilder(schemas map[string]interface{}) *schemaBuilder {
+	return &schemaBuilder{schemas: schemas, names: make(map[reflect.Type]string)}
+}
+
+// schemaOf 返回值 v 的类型对应的 schema，v 为 nil 时返回 nil
+func (b *schemaBuilder) schemaOf(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	if t, ok := v.(reflect.Type); ok {
+		return b.schema(t)
+	}
+	return b.schema(reflect.TypeOf(v))
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + b.structName(t)}
+	}
+	return map[string]interface{}{}
+}
+
+// structName 返回结构体的 schema 名称，首次遇到时生成 schema；不同包的同名类型加包名前缀区分
+func (b *schemaBuilder) structName(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := b.schemas[name]; taken {
+		pkg := t.PkgPath()
+		if i := strings.LastIndex(pkg, "/"); i >= 0 {
+			pkg = pkg[i+1:]
+		}
+		name = pkg + "." + name
+	}
+	// 先占位，支持自引用类型
+	b.names[t] = name
+	b.schemas[name] = map[string]interface{}{}
+	b.schemas[name] = b.structSchema(t)
+	return name
+}
+
+// structSchema 生成结构体的对象 schema，匿名嵌入的结构体字段会被展开
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	var required []string
+	b.collectFields(t, props, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (b *schemaBuilder) collectFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			b.collectFields(ft, props, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var schema map[string]interface{}
+		if strings.Contains(","+opts+",", ",string,") {
+			schema = map[string]interface{}{"type": "string"}
+		} else {
+			schema = b.schema(field.Type)
+		}
+		isRequired := applyFieldTags(schema, field)
+		// $ref 不能与其他关键字并列（OpenAPI 3.0），需要附加信息时包一层 allOf
+		if ref, ok := schema["$ref"]; ok && len(schema) > 1 {
+			delete(schema, "$ref")
+			schema["allOf"] = []interface{}{map[string]interface{}{"$ref": ref}}
+		}
+		props[name] = schema
+		if isRequired {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// applyFieldTags 将 example、enums、description、format、default 以及 binding/validate 规则写入 schema，
+// 返回字段是否必填
+func applyFieldTags(schema map[string]interface{}, field reflect.StructField) bool {
+	typ := getString(schema, "type")
+	itemType := typ
+	if typ == "array" {
+		items, _ := schema["items"].(map[string]interface{})
+		itemType = getString(items, "type")
+	}
+
+	if desc := field.Tag.Get("description"); desc != "" {
+		schema["description"] = desc
+	}
+	if format := field.Tag.Get("format"); format != "" {
+		schema["format"] = format
+	}
+	if v, ok := field.Tag.Lookup("example"); ok {
+		schema["example"] = parseTagValue(v, typ, itemType)
+	}
+	if v, ok := field.Tag.Lookup("default"); ok {
+		schema["default"] = parseTagValue(v, typ, itemType)
+	}
+	if v := field.Tag.Get("enums"); v != "" {
+		setEnum(schema, typ, itemType, strings.Split(v, ","))
+	}
+
+	required := false
+	for _, key := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(field.Tag.Get(key), ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			switch name {
+			case "required":
+				required = true
+			case "min", "gte":
+				setBound(schema, typ, "min", arg, false)
+			case "max", "lte":
+				setBound(schema, typ, "max", arg, false)
+			case "gt":
+				setBound(schema, typ, "min", arg, true)
+			case "lt":
+				setBound(schema, typ, "max", arg, true)
+			case "len":
+				setBound(schema, typ, "min", arg, false)
+				setBound(schema, typ, "max", arg, false)
+			case "oneof":
+				setEnum(schema, typ, itemType, strings.Fields(arg))
+			case "email", "uuid", "uri", "url", "ipv4", "ipv6", "hostname", "datetime":
+				formats := map[string]string{"url": "uri", "datetime": "date-time"}
+				if f, ok := formats[name]; ok {
+					name = f
+				}
+				schema["format"] = name
+			}
+		}
+	}
+	return required
+}
+
+// setBound 按类型设置 binding/validate 中的 min/max 约束：字符串为长度、数组为元素个数、数字为取值范围
+func setBound(schema map[string]interface{}, typ, bound, arg string, exclusive bool) {
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return
+	}
+	var key string
+	switch typ {
+	case "string":
+		key = bound + "Length"
+	case "array":
+		key = bound + "Items"
+	case "object":
+		key = bound + "Properties"
+	case "integer", "number":
+		key = map[string]string{"min": "minimum", "max": "maximum"}[bound]
+		if exclusive {
+			schema["exclusive"+strings.ToUpper(key[:1])+key[1:]] = true
+		}
+		schema[key] = n
+		return
+	default:
+		return
+	}
+	schema[key] = int(n)
+}
+
+// setEnum 设置枚举值，数组类型写入 items
+func setEnum(schema map[string]interface{}, typ, itemType string, values []string) {
+	enum := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		enum = append(enum, parseScalar(strings.TrimSpace(v), itemType))
+	}
+	if typ == "array" {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			items["enum"] = enum
+		}
+		return
+	}
+	schema["enum"] = enum
+}
+
+// parseTagValue 按字段类型解析标签中的示例值，数组以逗号分隔，对象按 JSON 解析
+func parseTagValue(v, typ, itemType string) interface{} {
+	switch typ {
+	case "array":
+		var list []interface{}
+		if json.Unmarshal([]byte(v), &list) == nil {
+			return list
+		}
+		list = []interface{}{}
+		for _, item := range strings.Split(v, ",") {
+			list = append(list, parseScalar(strings.TrimSpace(item), itemType))
+		}
+		return list
+	case "object", "":
+		var obj interface{}
+		if json.Unmarshal([]byte(v), &obj) == nil {
+			return obj
+		}
+		return v
+	}
+	return parseScalar(v, typ)
+}
+
+// parseScalar 按 schema 类型转换标量值，转换失败时保留字符串
+func parseScalar(v, typ string) interface{} {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
@@ -0,0 +1,496 @@
+package qingfeng
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSchemaDepth schema 递归处理的最大深度（与前端 generateExample 保持一致）
+const maxSchemaDepth = 10
+
+// ValidationError 描述一处不符合文档约束的值
+// 字段含义与 UI 中 Model 视图的展示保持一致：字段名、类型、是否必填及约束
+type ValidationError struct {
+	// In 值的位置：path、query、header、cookie、formData、body、response
+	In string `json:"in"`
+	// Field 字段路径，如 "id"、"user.tags[0]"，请求体根为空
+	Field string `json:"field,omitempty"`
+	// Type 文档声明的类型，格式与 UI 一致（如 "string"、"User"、"integer[]"）
+	Type string `json:"type,omitempty"`
+	// Rule 触发的约束：required、type、enum、format、minimum、maxLength 等
+	Rule string `json:"rule"`
+	// Expected 约束的期望值（如枚举列表、最小值）
+	Expected interface{} `json:"expected,omitempty"`
+	// Message 可读的错误描述
+	Message string `json:"message"`
+}
+
+// Error 实现 error 接口
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", e.In, e.Message)
+	}
+	return fmt.Sprintf("%s.%s: %s", e.In, e.Field, e.Message)
+}
+
+// normalizeSpec 解析文档并统一转换为 OpenAPI 3 格式
+func normalizeSpec(data []byte) map[string]interface{} {
+	if detectSpecFormat(data) == "swagger2" {
+		if converted, err := convertSwagger2ToOpenAPI3(data); err == nil {
+			data = converted
+		}
+	}
+	return parseSpec(data)
+}
+
+// resolveRef 解析文档内部的 $ref（JSON Pointer）
+// 与前端一致，#/definitions/ 找不到时回退到 #/components/schemas/
+func resolveRef(spec map[string]interface{}, ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var cur interface{} = spec
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			cur = nil
+			break
+		}
+		cur = m[part]
+	}
+	if result, ok := cur.(map[string]interface{}); ok {
+		return result
+	}
+	if strings.HasPrefix(ref, "#/definitions/") {
+		return resolveRef(spec, "#/components/schemas/"+strings.TrimPrefix(ref, "#/definitions/"))
+	}
+	return nil
+}
+
+// derefSchema 沿 $ref 链解析 schema，直到得到非引用的 schema
+func derefSchema(spec, schema map[string]interface{}) map[string]interface{} {
+	for i := 0; i < maxSchemaDepth && schema != nil; i++ {
+		ref := getString(schema, "$ref")
+		if ref == "" {
+			return schema
+		}
+		schema = resolveRef(spec, ref)
+	}
+	return schema
+}
+
+// schemaTypeName 返回 schema 的展示类型，规则与前端 getSchemaType 相同
+func schemaTypeName(schema map[string]interface{}) string {
+	if schema == nil {
+		return ""
+	}
+	if ref := getString(schema, "$ref"); ref != "" {
+		parts := strings.Split(ref[strings.LastIndex(ref, "/")+1:], ".")
+		return parts[len(parts)-1]
+	}
+	t := schemaType(schema)
+	if t == "array" {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			return schemaTypeName(items) + "[]"
+		}
+		return "array"
+	}
+	if t == "" {
+		return "object"
+	}
+	return t
+}
+
+// schemaType 返回 schema 的 type；OpenAPI 3.1 的类型数组取第一个非 null 类型
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+// schemaNullable 判断 schema 是否允许 null
+func schemaNullable(schema map[string]interface{}) bool {
+	if v, ok := schema["nullable"].(bool); ok && v {
+		return true
+	}
+	if types, ok := schema["type"].([]interface{}); ok {
+		for _, t := range types {
+			if t == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateExample 根据 schema 生成示例值，与前端 generateExample 逻辑一致，
+// 并按 format 生成更贴近真实的数据
+func generateExample(spec, schema map[string]interface{}, depth int) interface{} {
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+	if ref := getString(schema, "$ref"); ref != "" {
+		resolved := resolveRef(spec, ref)
+		if resolved == nil {
+			return map[string]interface{}{}
+		}
+		return generateExample(spec, resolved, depth+1)
+	}
+
+	// 处理 allOf - 合并所有子 schema
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, sub := range allOf {
+			subSchema, _ := sub.(map[string]interface{})
+			if obj, ok := generateExample(spec, subSchema, depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+
+	if v, ok := schema["example"]; ok {
+		return v
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if v, ok := schema["default"]; ok {
+		return v
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if list, ok := schema[key].([]interface{}); ok && len(list) > 0 {
+			sub, _ := list[0].(map[string]interface{})
+			return generateExample(spec, sub, depth+1)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		return exampleString(getString(schema, "format"))
+	case "integer":
+		if v, ok := schema["minimum"].(float64); ok {
+			return math.Ceil(v)
+		}
+		return 0
+	case "number":
+		if v, ok := schema["minimum"].(float64); ok {
+			return v
+		}
+		return 0
+	case "boolean":
+		return true
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		if items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{generateExample(spec, items, depth+1)}
+	default:
+		obj := make(map[string]interface{})
+		if props, ok := schema["properties"].(map[string]interface{}); ok {
+			for key, p := range props {
+				prop, _ := p.(map[string]interface{})
+				obj[key] = generateExample(spec, prop, depth+1)
+			}
+		}
+		if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok && len(obj) == 0 {
+			obj["key"] = generateExample(spec, extra, depth+1)
+		}
+		return obj
+	}
+}
+
+// exampleString 按 format 生成字符串示例
+func exampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.168.0.1"
+	case "ipv6":
+		return "::1"
+	case "byte":
+		return "c3RyaW5n"
+	case "password":
+		return "********"
+	}
+	return "string"
+}
+
+// validateValue 按 schema 校验 JSON 值（已由 encoding/json 解码）
+func validateValue(spec, schema map[string]interface{}, value interface{}, in, field string) []ValidationError {
+	return validateValueDepth(spec, schema, value, in, field, 0)
+}
+
+func validateValueDepth(spec, schema map[string]interface{}, value interface{}, in, field string, depth int) []ValidationError {
+	if schema == nil || depth > maxSchemaDepth*2 {
+		return nil
+	}
+	typeName := schemaTypeName(schema)
+	schema = derefSchema(spec, schema)
+	if schema == nil {
+		return nil
+	}
+
+	fail := func(rule string, expected interface{}, format string, args ...interface{}) []ValidationError {
+		return []ValidationError{{In: in, Field: field, Type: typeName, Rule: rule, Expected: expected, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if value == nil {
+		if schemaNullable(schema) || len(schema) == 0 {
+			return nil
+		}
+		return fail("type", typeName, "不能为 null")
+	}
+
+	var errs []ValidationError
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			subSchema, _ := sub.(map[string]interface{})
+			errs = append(errs, validateValueDepth(spec, subSchema, value, in, field, depth+1)...)
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		list, ok := schema[key].([]interface{})
+		if !ok || len(list) == 0 {
+			continue
+		}
+		matched := false
+		for _, sub := range list {
+			subSchema, _ := sub.(map[string]interface{})
+			if len(validateValueDepth(spec, subSchema, value, in, field, depth+1)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			errs = append(errs, fail(key, nil, "不匹配 %s 中的任何 schema", key)...)
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fail("enum", enum, "值 %v 不在枚举范围内", value)...)
+		}
+	}
+
+	switch t := schemaType(schema); t {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return append(errs, fail("type", t, "应为 string 类型")...)
+		}
+		length := float64(len([]rune(s)))
+		if v, ok := schema["minLength"].(float64); ok && length < v {
+			errs = append(errs, fail("minLength", v, "长度不能小于 %v", v)...)
+		}
+		if v, ok := schema["maxLength"].(float64); ok && length > v {
+			errs = append(errs, fail("maxLength", v, "长度不能大于 %v", v)...)
+		}
+		if p := getString(schema, "pattern"); p != "" {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(s) {
+				errs = append(errs, fail("pattern", p, "不匹配正则 %s", p)...)
+			}
+		}
+		if format := getString(schema, "format"); format != "" && !validFormat(format, s) {
+			errs = append(errs, fail("format", format, "不是合法的 %s 格式", format)...)
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return append(errs, fail("type", t, "应为 %s 类型", t)...)
+		}
+		if t == "integer" && n != math.Trunc(n) {
+			return append(errs, fail("type", t, "应为 integer 类型")...)
+		}
+		errs = append(errs, validateNumber(schema, n, fail)...)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, fail("type", t, "应为 boolean 类型")...)
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return append(errs, fail("type", t, "应为 array 类型")...)
+		}
+		if v, ok := schema["minItems"].(float64); ok && float64(len(arr)) < v {
+			errs = append(errs, fail("minItems", v, "元素个数不能少于 %v", v)...)
+		}
+		if v, ok := schema["maxItems"].(float64); ok && float64(len(arr)) > v {
+			errs = append(errs, fail("maxItems", v, "元素个数不能多于 %v", v)...)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range arr {
+				errs = append(errs, validateValueDepth(spec, items, item, in, fmt.Sprintf("%s[%d]", field, i), depth+1)...)
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, fail("type", t, "应为 object 类型")...)
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, r := range getStringArray(schema, "required") {
+			if _, ok := obj[r]; !ok {
+				prop, _ := props[r].(map[string]interface{})
+				errs = append(errs, ValidationError{In: in, Field: joinField(field, r), Type: schemaTypeName(prop), Rule: "required", Message: "缺少必填字段"})
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			v := obj[key]
+			if prop, ok := props[key].(map[string]interface{}); ok {
+				errs = append(errs, validateValueDepth(spec, prop, v, in, joinField(field, key), depth+1)...)
+			} else if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				errs = append(errs, validateValueDepth(spec, extra, v, in, joinField(field, key), depth+1)...)
+			} else if allowed, ok := schema["additionalProperties"].(bool); ok && !allowed {
+				errs = append(errs, ValidationError{In: in, Field: joinField(field, key), Rule: "additionalProperties", Message: "不允许的字段"})
+			}
+		}
+	}
+	return errs
+}
+
+// validateNumber 校验数值范围约束（兼容 OpenAPI 3.0 布尔型与 3.1 数值型 exclusiveMinimum/Maximum）
+func validateNumber(schema map[string]interface{}, n float64, fail func(string, interface{}, string, ...interface{}) []ValidationError) []ValidationError {
+	var errs []ValidationError
+	exclusiveMin, _ := schema["exclusiveMinimum"].(bool)
+	exclusiveMax, _ := schema["exclusiveMaximum"].(bool)
+	if v, ok := schema["minimum"].(float64); ok {
+		if n < v || (exclusiveMin && n == v) {
+			errs = append(errs, fail("minimum", v, "不能小于 %v", v)...)
+		}
+	}
+	if v, ok := schema["maximum"].(float64); ok {
+		if n > v || (exclusiveMax && n == v) {
+			errs = append(errs, fail("maximum", v, "不能大于 %v", v)...)
+		}
+	}
+	if v, ok := schema["exclusiveMinimum"].(float64); ok && n <= v {
+		errs = append(errs, fail("exclusiveMinimum", v, "必须大于 %v", v)...)
+	}
+	if v, ok := schema["exclusiveMaximum"].(float64); ok && n >= v {
+		errs = append(errs, fail("exclusiveMaximum", v, "必须小于 %v", v)...)
+	}
+	if v, ok := schema["multipleOf"].(float64); ok && v > 0 && math.Abs(math.Remainder(n, v)) > 1e-9 {
+		errs = append(errs, fail("multipleOf", v, "必须是 %v 的倍数", v)...)
+	}
+	return errs
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat 校验常见的字符串 format，未知 format 视为合法
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	}
+	return true
+}
+
+// joinField 拼接字段路径
+func joinField(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// coerceParam 将字符串形式的参数值（路径、查询、请求头）按 schema 转换为 JSON 值
+func coerceParam(spec, schema map[string]interface{}, values []string) interface{} {
+	schema = derefSchema(spec, schema)
+	if schema == nil || len(values) == 0 {
+		if len(values) == 0 {
+			return nil
+		}
+		return values[0]
+	}
+	if schemaType(schema) == "array" {
+		if len(values) == 1 && strings.Contains(values[0], ",") {
+			values = strings.Split(values[0], ",")
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		arr := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			arr = append(arr, coerceParam(spec, items, []string{v}))
+		}
+		return arr
+	}
+
+	raw := values[0]
+	switch schemaType(schema) {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	case "object":
+		var obj interface{}
+		if json.Unmarshal([]byte(raw), &obj) == nil {
+			return obj
+		}
+	}
+	return raw
+}
@@ -0,0 +1,610 @@
+package qingfeng
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 搜索字段权重：命中路径、摘要等标识性字段的接口排在前面
+const (
+	searchWeightPath        = 5
+	searchWeightOperationID = 4
+	searchWeightSummary     = 4
+	searchWeightTag         = 3
+	searchWeightParameter   = 2
+	searchWeightProperty    = 1.5
+	searchWeightDescription = 1
+)
+
+// searchPrefixFactor 前缀匹配（如 "use" 匹配 "users"）的得分系数
+const searchPrefixFactor = 0.6
+
+// searchPhraseBonus 完整查询文本出现在路径或摘要中时的额外得分
+const searchPhraseBonus = 5
+
+// searchDeprecatedFactor 已废弃接口的得分系数，使其排在同等匹配的正常接口之后
+const searchDeprecatedFactor = 0.8
+
+// searchSnippetRunes 描述摘录的长度（字符数）
+const searchSnippetRunes = 120
+
+// searchDoc 索引中的一个接口或文档页面
+type searchDoc struct {
+	// page 文档页面的 slug，接口为空
+	page        string
+	method      string
+	path        string
+	summary     string
+	description string
+	operationID string
+	tags        []string
+	deprecated  bool
+	parameters  []string
+	properties  []string
+}
+
+// searchIndex 接口倒排索引：term → 接口序号 → 该词在接口中命中字段的最高权重
+type searchIndex struct {
+	docs     []*searchDoc
+	postings map[string]map[int]float64
+	// terms 有序词表，用于前缀匹配
+	terms []string
+}
+
+// searchResult 一条搜索结果
+type searchResult struct {
+	// Page 命中文档页面时为页面 slug，此时 Summary 为页面标题，Method 与 Path 为空
+	Page        string   `json:"page,omitempty"`
+	Method      string   `json:"method,omitempty"`
+	Path        string   `json:"path,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	OperationID string   `json:"operationId,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	Score       float64  `json:"score"`
+	// Highlights 已转义的 HTML 片段，命中部分以 <mark> 包裹（path、summary、description）
+	Highlights map[string]string `json:"highlights,omitempty"`
+	// Matches 命中的参数与字段，如 "parameter:userId"、"property:email"
+	Matches []string `json:"matches,omitempty"`
+}
+
+// searchQuery 解析后的查询：自由文本与过滤条件
+type searchQuery struct {
+	text       string
+	tokens     []string
+	methods    []string
+	tags       []string
+	deprecated *bool
+}
+
+// buildSearchIndex 为文档中的所有接口建立倒排索引，覆盖路径、摘要、描述、参数名、schema 字段名与标签
+func buildSearchIndex(spec map[string]interface{}) *searchIndex {
+	idx := &searchIndex{postings: make(map[string]map[int]float64)}
+	forEachOperation(spec, func(path, method string, op map[string]interface{}) {
+		doc := &searchDoc{
+			method:      method,
+			path:        path,
+			summary:     getString(op, "summary"),
+			description: getString(op, "description"),
+			operationID: getString(op, "operationId"),
+			tags:        getStringArray(op, "tags"),
+		}
+		doc.deprecated, _ = op["deprecated"].(bool)
+		for _, param := range operationParameters(spec, path, op) {
+			if name := getString(param, "name"); name != "" {
+				doc.parameters = append(doc.parameters, name)
+			}
+		}
+		doc.properties = operationPropertyNames(spec, op)
+
+		id := len(idx.docs)
+		idx.docs = append(idx.docs, doc)
+		idx.add(id, path, searchWeightPath)
+		idx.add(id, doc.operationID, searchWeightOperationID)
+		idx.add(id, doc.summary, searchWeightSummary)
+		idx.add(id, doc.description, searchWeightDescription)
+		for _, tag := range doc.tags {
+			idx.add(id, tag, searchWeightTag)
+		}
+		for _, name := range doc.parameters {
+			idx.add(id, name, searchWeightParameter)
+		}
+		for _, name := range doc.properties {
+			idx.add(id, name, searchWeightProperty)
+		}
+	})
+	idx.sortTerms()
+	return idx
+}
+
+// buildPageSearchIndex 为文档页面建立索引，覆盖标题、小标题与正文
+func buildPageSearchIndex(pages []*guidePage) *searchIndex {
+	idx := &searchIndex{postings: make(map[string]map[int]float64)}
+	for _, page := range pages {
+		id := len(idx.docs)
+		idx.docs = append(idx.docs, &searchDoc{page: page.Slug, summary: page.Title, description: page.Text})
+		idx.add(id, page.Title, searchWeightSummary)
+		for _, heading := range page.headings {
+			idx.add(id, heading, searchWeightTag)
+		}
+		idx.add(id, page.Text, searchWeightDescription)
+	}
+	idx.sortTerms()
+	return idx
+}
+
+// sortTerms 生成用于前缀匹配的有序词表
+func (idx *searchIndex) sortTerms() {
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+}
+
+func (idx *searchIndex) add(id int, text string, weight float64) {
+	for _, term := range indexTokens(text) {
+		docs, ok := idx.postings[term]
+		if !ok {
+			docs = make(map[int]float64)
+			idx.postings[term] = docs
+		}
+		if weight > docs[id] {
+			docs[id] = weight
+		}
+	}
+}
+
+// operationPropertyNames 收集请求体与响应 schema 中的字段名（展开 $ref、allOf 等组合，递归 schema 只访问一次）
+func operationPropertyNames(spec, op map[string]interface{}) []string {
+	var names []string
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	var walk func(schema map[string]interface{}, depth int)
+	walk = func(schema map[string]interface{}, depth int) {
+		if schema == nil || depth > maxSchemaDepth {
+			return
+		}
+		if ref := getString(schema, "$ref"); ref != "" {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+			walk(resolveRef(spec, ref), depth+1)
+			return
+		}
+		if props, ok := schema["properties"].(map[string]interface{}); ok {
+			for _, name := range sortedKeys(props) {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+				prop, _ := props[name].(map[string]interface{})
+				walk(prop, depth+1)
+			}
+		}
+		for _, key := range []string{"items", "additionalProperties"} {
+			if sub, ok := schema[key].(map[string]interface{}); ok {
+				walk(sub, depth+1)
+			}
+		}
+		for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+			list, _ := schema[key].([]interface{})
+			for _, item := range list {
+				sub, _ := item.(map[string]interface{})
+				walk(sub, depth+1)
+			}
+		}
+	}
+
+	walkContent := func(container map[string]interface{}) {
+		content, _ := container["content"].(map[string]interface{})
+		for _, mediaType := range contentKeys(content) {
+			media, _ := content[mediaType].(map[string]interface{})
+			schema, _ := media["schema"].(map[string]interface{})
+			walk(schema, 0)
+		}
+	}
+	if body := operationRequestBody(spec, op); body != nil {
+		walkContent(body)
+	}
+	responses, _ := op["responses"].(map[string]interface{})
+	for _, code := range sortedKeys(responses) {
+		response, _ := responses[code].(map[string]interface{})
+		if ref := getString(response, "$ref"); ref != "" {
+			response = resolveRef(spec, ref)
+		}
+		if response != nil {
+			walkContent(response)
+		}
+	}
+	return names
+}
+
+// indexTokens 切分待索引文本：按非字母数字（含下划线）切分并转小写，驼峰命名额外拆分为单词，
+// 汉字逐字成词（如 "getUserById" → getuserbyid、get、user、by、id）
+func indexTokens(text string) []string {
+	var tokens []string
+	for _, word := range splitWords(text) {
+		tokens = append(tokens, strings.ToLower(word))
+		if parts := camelParts(word); len(parts) > 1 {
+			for _, part := range parts {
+				tokens = append(tokens, strings.ToLower(part))
+			}
+		}
+	}
+	return tokens
+}
+
+// splitWords 按非字母数字切分文本，汉字单独成词
+func splitWords(text string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, string(cur))
+			cur = cur[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			words = append(words, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			cur = append(cur, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// camelParts 拆分驼峰命名（"userID" → user、ID；"HTTPServer" → HTTP、Server）
+func camelParts(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, r := runes[i-1], runes[i]
+		boundary := unicode.IsLower(prev) && unicode.IsUpper(r) ||
+			unicode.IsLetter(prev) != unicode.IsLetter(r) ||
+			unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if boundary {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// parseSearchQuery 解析查询，支持 method:post、tag:User（值可加引号）、deprecated:true 过滤条件，
+// 其余部分作为自由文本
+func parseSearchQuery(q string) searchQuery {
+	var query searchQuery
+	var text []string
+	for _, field := range splitQueryFields(q) {
+		key, value, ok := strings.Cut(field, ":")
+		value = strings.Trim(value, `"`)
+		switch key = strings.ToLower(key); {
+		case ok && key == "method" && value != "":
+			query.methods = append(query.methods, strings.ToLower(value))
+		case ok && key == "tag" && value != "":
+			query.tags = append(query.tags, strings.ToLower(value))
+		case ok && key == "deprecated" && (value == "true" || value == "false"):
+			v := value == "true"
+			query.deprecated = &v
+		default:
+			text = append(text, strings.Trim(field, `"`))
+		}
+	}
+	query.text = strings.Join(text, " ")
+	for _, word := range splitWords(query.text) {
+		query.tokens = append(query.tokens, strings.ToLower(word))
+	}
+	return query
+}
+
+// splitQueryFields 按空白切分查询，引号内的空白不切分
+func splitQueryFields(q string) []string {
+	var fields []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
+
+// matches 判断接口是否满足过滤条件（同一过滤键的多个值为"或"关系）
+func (q searchQuery) matches(doc *searchDoc) bool {
+	if len(q.methods) > 0 && !containsString(q.methods, doc.method) {
+		return false
+	}
+	if q.deprecated != nil && doc.deprecated != *q.deprecated {
+		return false
+	}
+	if len(q.tags) > 0 {
+		found := false
+		for _, want := range q.tags {
+			for _, tag := range doc.tags {
+				// 多级标签：tag:Admin 同时匹配 Admin-User
+				tag = strings.ToLower(tag)
+				if tag == want || strings.HasPrefix(tag, want+"-") {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// termScores 返回一个查询词在各接口中的得分：完全匹配取字段权重，前缀匹配按系数折减
+func (idx *searchIndex) termScores(token string) map[int]float64 {
+	scores := make(map[int]float64)
+	for i := sort.SearchStrings(idx.terms, token); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], token); i++ {
+		term := idx.terms[i]
+		factor := 1.0
+		if term != token {
+			factor = searchPrefixFactor
+		}
+		for id, weight := range idx.postings[term] {
+			if s := weight * factor; s > scores[id] {
+				scores[id] = s
+			}
+		}
+	}
+	return scores
+}
+
+// search 执行查询：所有查询词都必须命中，按得分从高到低排序，最多返回 limit 条；
+// 第二个返回值为命中总数
+func (idx *searchIndex) search(q string, limit int) ([]searchResult, int) {
+	query := parseSearchQuery(q)
+
+	var scores map[int]float64
+	if len(query.tokens) > 0 {
+		for _, token := range query.tokens {
+			termScores := idx.termScores(token)
+			if scores == nil {
+				scores = termScores
+				continue
+			}
+			for id, s := range scores {
+				if ts, ok := termScores[id]; ok {
+					scores[id] = s + ts
+				} else {
+					delete(scores, id)
+				}
+			}
+		}
+	} else {
+		scores = make(map[int]float64, len(idx.docs))
+		for id := range idx.docs {
+			scores[id] = 0
+		}
+	}
+
+	phrase := strings.ToLower(strings.TrimSpace(query.text))
+	var ids []int
+	for id := range scores {
+		doc := idx.docs[id]
+		if !query.matches(doc) {
+			continue
+		}
+		if phrase != "" && (strings.Contains(strings.ToLower(doc.path), phrase) || strings.Contains(strings.ToLower(doc.summary), phrase)) {
+			scores[id] += searchPhraseBonus
+		}
+		if doc.deprecated {
+			scores[id] *= searchDeprecatedFactor
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	total := len(ids)
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	results := make([]searchResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, idx.result(idx.docs[id], scores[id], query.tokens))
+	}
+	return results, total
+}
+
+// result 生成搜索结果，并为命中的字段生成高亮片段
+func (idx *searchIndex) result(doc *searchDoc, score float64, tokens []string) searchResult {
+	r := searchResult{
+		Page:        doc.page,
+		Method:      doc.method,
+		Path:        doc.path,
+		Summary:     doc.summary,
+		OperationID: doc.operationID,
+		Tags:        doc.tags,
+		Deprecated:  doc.deprecated,
+		Score:       float64(int(score*100+0.5)) / 100,
+	}
+	if len(tokens) == 0 {
+		return r
+	}
+	r.Highlights = make(map[string]string)
+	if h, ok := highlightText(doc.path, tokens); ok {
+		r.Highlights["path"] = h
+	}
+	if h, ok := highlightText(doc.summary, tokens); ok {
+		r.Highlights["summary"] = h
+	}
+	if h, ok := highlightText(snippetAround(doc.description, tokens), tokens); ok {
+		r.Highlights["description"] = h
+	}
+	for _, name := range doc.parameters {
+		if containsAnyToken(name, tokens) {
+			r.Matches = append(r.Matches, "parameter:"+name)
+		}
+	}
+	for _, name := range doc.properties {
+		if containsAnyToken(name, tokens) {
+			r.Matches = append(r.Matches, "property:"+name)
+		}
+	}
+	return r
+}
+
+// mergeSearchResults 合并接口与文档页面的搜索结果，按得分排序后最多保留 limit 条
+func mergeSearchResults(results, more []searchResult, limit int) []searchResult {
+	merged := append(append(make([]searchResult, 0, len(results)+len(more)), results...), more...)
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Score > merged[j].Score })
+	if limit > 0 && len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+func containsAnyToken(text string, tokens []string) bool {
+	lower := strings.ToLower(text)
+	for _, token := range tokens {
+		if strings.Contains(lower, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightText 转义文本并以 <mark> 包裹命中的查询词，未命中时第二个返回值为 false
+func highlightText(text string, tokens []string) (string, bool) {
+	lower := strings.ToLower(text)
+	if text == "" || len(lower) != len(text) {
+		// 大小写转换改变了字节长度时无法按位置高亮
+		return "", false
+	}
+	marked := make([]bool, len(text))
+	hit := false
+	for _, token := range tokens {
+		for start := 0; ; {
+			i := strings.Index(lower[start:], token)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(token); j++ {
+				marked[j] = true
+			}
+			hit = true
+			start += i + len(token)
+		}
+	}
+	if !hit {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(text); {
+		j := i
+		for j < len(text) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			fmt.Fprintf(&buf, "<mark>%s</mark>", html.EscapeString(text[i:j]))
+		} else {
+			buf.WriteString(html.EscapeString(text[i:j]))
+		}
+		i = j
+	}
+	return buf.String(), true
+}
+
+// snippetAround 截取描述中第一个命中词附近的片段
+func snippetAround(text string, tokens []string) string {
+	if utf8.RuneCountInString(text) <= searchSnippetRunes {
+		return text
+	}
+	lower := strings.ToLower(text)
+	pos := -1
+	for _, token := range tokens {
+		if i := strings.Index(lower, token); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+	if pos < 0 || len(lower) != len(text) {
+		return truncateString(text, searchSnippetRunes)
+	}
+	runes := []rune(text)
+	center := utf8.RuneCountInString(text[:pos])
+	start := center - searchSnippetRunes/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + searchSnippetRunes
+	if end > len(runes) {
+		end = len(runes)
+	}
+	snippet := string(runes[start:end])
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(runes) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// searchStore 按文档内容缓存搜索索引，文档变化时重建
+type searchStore struct {
+	mu    sync.Mutex
+	src   []byte
+	index *searchIndex
+}
+
+// get 返回文档对应的索引
+func (s *searchStore) get(spec []byte) (*searchIndex, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index != nil && bytes.Equal(s.src, spec) {
+		return s.index, nil
+	}
+	doc := parseSpec(spec)
+	if doc == nil {
+		return nil, fmt.Errorf("解析文档失败")
+	}
+	s.src, s.index = spec, buildSearchIndex(doc)
+	return s.index, nil
+}
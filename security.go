@@ -0,0 +1,262 @@
+package qingfeng
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SecurityConfig configures CORS and security response headers of the documentation routes
+// 安全配置：控制文档与配置接口的跨域策略，以及 Content-Security-Policy、X-Frame-Options、Referrer-Policy 等响应头。
+// 未配置 Config.Security 时保持原有行为：文档接口返回 Access-Control-Allow-Origin: *，不发送安全响应头
+type SecurityConfig struct {
+	// AllowedOrigins 允许跨域读取文档与配置的来源，如 "https://portal.example.com"；
+	// 支持 "https://*.example.com" 匹配子域名，"*" 允许任意来源；为空时不允许跨域读取
+	AllowedOrigins []string
+	// AllowedMethods 跨域允许的方法，默认 GET、HEAD、OPTIONS
+	AllowedMethods []string
+	// AllowedHeaders 预检请求允许的请求头，默认 Content-Type、If-None-Match
+	AllowedHeaders []string
+	// AllowCredentials 允许跨域请求携带 Cookie 等凭证（此时响应回显请求的 Origin 而不是 *）
+	AllowCredentials bool
+	// MaxAge 预检结果的缓存时间（秒），默认 600
+	MaxAge int
+
+	// ContentSecurityPolicy 完整的 CSP，为空时使用兼容内置主题的默认策略，"-" 表示不发送。
+	// 默认策略的 script-src 不含 'unsafe-inline'，自定义主题（RegisterTheme、Config.CustomThemes）使用内联脚本或 onclick 等事件属性时，
+	// 需经 ExtraCSP 追加 'unsafe-inline' 或改为外部脚本
+	ContentSecurityPolicy string
+	// ExtraCSP 在默认策略的指令上追加来源，如 {"script-src": {"https://analytics.example.com"}}
+	ExtraCSP map[string][]string
+	// FrameAncestors 允许以 iframe 嵌入文档页面的来源（CSP frame-ancestors），默认 'self'，
+	// []string{"'none'"} 禁止嵌入；X-Frame-Options 据此发送 DENY 或 SAMEORIGIN（允许其他来源时不发送）
+	FrameAncestors []string
+	// ReferrerPolicy 默认 strict-origin-when-cross-origin
+	ReferrerPolicy string
+}
+
+// corsRoutes 受跨域策略控制的文档与配置接口
+var corsRoutes = map[string]bool{
+	"/swagger.json": true, "/openapi.json": true, "/api-docs": true, "/doc.json": true,
+	"/config.json": true, "/index.json": true, "/operation": true, "/bundle.json": true,
+	"/pages.json": true,
+}
+
+// securityPolicy 根据 SecurityConfig 生成的响应头
+type securityPolicy struct {
+	cfg          SecurityConfig
+	frameOptions string
+	// connectSources、imgSources 默认 CSP 中与文档无关的固定来源（环境地址、Logo）
+	connectSources []string
+	imgSources     []string
+
+	mu     sync.Mutex
+	src    []byte
+	policy string
+}
+
+// newSecurityPolicy 创建安全策略，未配置 Config.Security 时返回 nil
+func newSecurityPolicy(cfg Config) *securityPolicy {
+	if cfg.Security == nil {
+		return nil
+	}
+	c := *cfg.Security
+	if len(c.AllowedMethods) == 0 {
+		c.AllowedMethods = []string{"GET", "HEAD", "OPTIONS"}
+	}
+	if len(c.AllowedHeaders) == 0 {
+		c.AllowedHeaders = []string{"Content-Type", "If-None-Match"}
+	}
+	if c.MaxAge == 0 {
+		c.MaxAge = 600
+	}
+	if len(c.FrameAncestors) == 0 {
+		c.FrameAncestors = []string{"'self'"}
+	}
+	if c.ReferrerPolicy == "" {
+		c.ReferrerPolicy = "strict-origin-when-cross-origin"
+	}
+
+	p := &securityPolicy{cfg: c}
+	switch strings.Join(c.FrameAncestors, " ") {
+	case "'none'":
+		p.frameOptions = "DENY"
+	case "'self'":
+		p.frameOptions = "SAMEORIGIN"
+	}
+	for _, env := range cfg.Environments {
+		p.connectSources = appendOrigin(p.connectSources, env.BaseURL)
+	}
+	p.imgSources = appendOrigin(p.imgSources, cfg.Logo)
+	return p
+}
+
+// setHeaders 写出 CSP、X-Frame-Options、Referrer-Policy 与 X-Content-Type-Options
+func (p *securityPolicy) setHeaders(w http.ResponseWriter, spec []byte) {
+	h := w.Header()
+	if policy := p.contentSecurityPolicy(spec); policy != "" {
+		h.Set("Content-Security-Policy", policy)
+	}
+	if p.frameOptions != "" {
+		h.Set("X-Frame-Options", p.frameOptions)
+	}
+	h.Set("Referrer-Policy", p.cfg.ReferrerPolicy)
+	h.Set("X-Content-Type-Options", "nosniff")
+}
+
+// contentSecurityPolicy 返回 CSP；默认策略按文档中的 servers 放行在线调试的请求地址，文档不变时复用
+func (p *securityPolicy) contentSecurityPolicy(spec []byte) string {
+	switch p.cfg.ContentSecurityPolicy {
+	case "-":
+		return ""
+	case "":
+	default:
+		return p.cfg.ContentSecurityPolicy
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.policy != "" && bytes.Equal(p.src, spec) {
+		return p.policy
+	}
+
+	connect := append([]string(nil), p.connectSources...)
+	doc := parseSpec(spec)
+	for _, server := range specServerURLs(doc) {
+		connect = appendOrigin(connect, server)
+	}
+	// 认证对话框直接请求 OAuth2 令牌端点
+	for _, tokenURL := range oauth2TokenURLs(doc) {
+		connect = appendOrigin(connect, tokenURL)
+	}
+	// 脚本只允许同源文件与带 nonce 的标签；内置主题使用 style 属性，样式需要 'unsafe-inline'，
+	// 其余来源均限制为同源
+	directives := [][]string{
+		{"default-src", "'self'"},
+		{"script-src", "'self'"},
+		{"style-src", "'self'", "'unsafe-inline'"},
+		append([]string{"img-src", "'self'", "data:"}, p.imgSources...),
+		{"font-src", "'self'", "data:"},
+		append([]string{"connect-src", "'self'"}, connect...),
+		{"object-src", "'none'"},
+		{"base-uri", "'self'"},
+		{"form-action", "'self'"},
+		append([]string{"frame-ancestors"}, p.cfg.FrameAncestors...),
+	}
+	known := make(map[string]bool)
+	for i, d := range directives {
+		known[d[0]] = true
+		directives[i] = append(d, p.cfg.ExtraCSP[d[0]]...)
+	}
+	var extra []string
+	for name := range p.cfg.ExtraCSP {
+		if !known[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		directives = append(directives, append([]string{name}, p.cfg.ExtraCSP[name]...))
+	}
+
+	parts := make([]string, len(directives))
+	for i, d := range directives {
+		parts[i] = strings.Join(d, " ")
+	}
+	p.src, p.policy = spec, strings.Join(parts, "; ")
+	return p.policy
+}
+
+// handleCORS 为文档与配置接口写出跨域响应头；预检请求直接返回 204，此时返回 true
+func (p *securityPolicy) handleCORS(w http.ResponseWriter, r *http.Request) bool {
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	if origin == "" || !p.originAllowed(origin) {
+		if preflight {
+			w.WriteHeader(http.StatusForbidden)
+			return true
+		}
+		return false
+	}
+
+	if containsString(p.cfg.AllowedOrigins, "*") && !p.cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if !preflight {
+		h.Set("Access-Control-Expose-Headers", "ETag")
+		return false
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(p.cfg.AllowedMethods, ", "))
+	h.Set("Access-Control-Allow-Headers", strings.Join(p.cfg.AllowedHeaders, ", "))
+	h.Set("Access-Control-Max-Age", strconv.Itoa(p.cfg.MaxAge))
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// originAllowed 判断来源是否在 AllowedOrigins 中（支持 * 与 https://*.example.com）
+func (p *securityPolicy) originAllowed(origin string) bool {
+	return matchOrigin(p.cfg.AllowedOrigins, origin)
+}
+
+// matchOrigin 判断来源是否匹配列表中的任意一项（支持 * 与 https://*.example.com）
+func matchOrigin(origins []string, origin string) bool {
+	for _, allowed := range origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if scheme, host, ok := strings.Cut(allowed, "://*."); ok {
+			prefix := scheme + "://"
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(strings.ToLower(origin), "."+strings.ToLower(host)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// specServerURLs 返回文档中声明的服务地址（OpenAPI 3 servers 与 Swagger 2 host）
+func specServerURLs(spec map[string]interface{}) []string {
+	var urls []string
+	servers, _ := spec["servers"].([]interface{})
+	for _, s := range servers {
+		if server, ok := s.(map[string]interface{}); ok {
+			urls = append(urls, getString(server, "url"))
+		}
+	}
+	if host := getString(spec, "host"); host != "" {
+		schemes := getStringArray(spec, "schemes")
+		if len(schemes) == 0 {
+			schemes = []string{"http", "https"}
+		}
+		for _, scheme := range schemes {
+			urls = append(urls, scheme+"://"+host)
+		}
+	}
+	return urls
+}
+
+// appendOrigin 将绝对地址的来源（scheme://host）加入列表，相对地址与模板地址忽略
+func appendOrigin(list []string, raw string) []string {
+	if strings.Contains(raw, "{") {
+		return list
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return list
+	}
+	origin := u.Scheme + "://" + u.Host
+	if containsString(list, origin) {
+		return list
+	}
+	return append(list, origin)
+}
@@ -0,0 +1,257 @@
+package qingfeng
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// httpMethods OpenAPI 路径项中允许出现的 HTTP 方法
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// isHTTPMethod 判断路径项中的 key 是否为 HTTP 方法
+func isHTTPMethod(key string) bool {
+	key = strings.ToLower(key)
+	for _, m := range httpMethods {
+		if m == key {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSpec 将文档 JSON 解析为 map，解析失败返回 nil
+func parseSpec(data []byte) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil
+	}
+	return spec
+}
+
+// forEachOperation 按路径和方法排序遍历文档中的所有操作
+func forEachOperation(spec map[string]interface{}, fn func(path, method string, op map[string]interface{})) {
+	paths, ok := spec["paths"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	keys := make([]string, 0, len(paths))
+	for p := range paths {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+
+	for _, p := range keys {
+		item, ok := paths[p].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, m := range httpMethods {
+			if op, ok := item[m].(map[string]interface{}); ok {
+				fn(p, m, op)
+			}
+		}
+	}
+}
+
+// splitPath 按 / 拆分路径段
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchSegments 将实际请求路径与文档路径模板（如 /users/{id}）按路径段匹配，返回路径参数
+func matchSegments(tmplParts, pathParts []string) (map[string]string, bool) {
+	if len(tmplParts) != len(pathParts) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, tp := range tmplParts {
+		pp := pathParts[i]
+		if strings.HasPrefix(tp, "{") && strings.HasSuffix(tp, "}") {
+			if pp == "" {
+				return nil, false
+			}
+			if v, err := url.PathUnescape(pp); err == nil {
+				pp = v
+			}
+			params[tp[1:len(tp)-1]] = pp
+			continue
+		}
+		if tp != pp {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// specBasePaths 返回文档中声明的路径前缀（Swagger 2.0 basePath 与 OpenAPI 3 servers）
+func specBasePaths(spec map[string]interface{}) []string {
+	var prefixes []string
+	if bp := getString(spec, "basePath"); bp != "" && bp != "/" {
+		prefixes = append(prefixes, bp)
+	}
+	if servers, ok := spec["servers"].([]interface{}); ok {
+		for _, s := range servers {
+			server, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if u, err := url.Parse(getString(server, "url")); err == nil && u.Path != "" && u.Path != "/" {
+				prefixes = append(prefixes, u.Path)
+			}
+		}
+	}
+	return prefixes
+}
+
+// operationMatch 请求路径匹配到的文档操作
+type operationMatch struct {
+	Path       string                 `json:"path"`
+	Method     string                 `json:"method"`
+	PathParams map[string]string      `json:"pathParams,omitempty"`
+	op         map[string]interface{} // 匹配到的操作对象
+}
+
+// pathCandidates 返回实际请求路径及去掉已知前缀（文档 basePath/servers 与 prefixes）后的候选路径
+func pathCandidates(spec map[string]interface{}, path string, prefixes []string) []string {
+	if path == "" {
+		path = "/"
+	}
+	candidates := []string{path}
+	for _, prefix := range append(specBasePaths(spec), prefixes...) {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix != "" && strings.HasPrefix(path, prefix+"/") {
+			candidates = append(candidates, strings.TrimPrefix(path, prefix))
+		}
+	}
+	return candidates
+}
+
+// routeIndex 文档操作的路由表：按方法分组并预先拆分路径模板，每份文档只构建一次，
+// 避免每个请求都遍历并排序 paths
+type routeIndex struct {
+	routes  map[string][]route // 小写方法 -> 按路径排序的操作
+	methods []string           // 出现过的方法，按 httpMethods 的顺序
+}
+
+// route 路由表中的一个操作
+type route struct {
+	path     string
+	segments []string
+	op       map[string]interface{}
+}
+
+// newRouteIndex 构建文档的路由表，文档为 nil 时返回 nil
+func newRouteIndex(spec map[string]interface{}) *routeIndex {
+	if spec == nil {
+		return nil
+	}
+	idx := &routeIndex{routes: make(map[string][]route)}
+	forEachOperation(spec, func(p, m string, op map[string]interface{}) {
+		idx.routes[m] = append(idx.routes[m], route{path: p, segments: splitPath(p), op: op})
+	})
+	for _, m := range httpMethods {
+		if len(idx.routes[m]) > 0 {
+			idx.methods = append(idx.methods, m)
+		}
+	}
+	return idx
+}
+
+// matchOperation 依次用候选路径匹配文档中的操作，
+// 同一候选路径下优先选择路径参数最少（最具体）的模板
+func (idx *routeIndex) matchOperation(method string, candidates []string) *operationMatch {
+	if idx == nil {
+		return nil
+	}
+	method = strings.ToLower(method)
+	for _, candidate := range candidates {
+		parts := splitPath(candidate)
+		var best *operationMatch
+		for _, rt := range idx.routes[method] {
+			params, ok := matchSegments(rt.segments, parts)
+			if !ok {
+				continue
+			}
+			if best == nil || len(params) < len(best.PathParams) {
+				best = &operationMatch{Path: rt.path, Method: method, PathParams: params, op: rt.op}
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+	return nil
+}
+
+// allowedMethods 返回与候选路径匹配的所有操作方法（用于 405 响应的 Allow 头）
+func (idx *routeIndex) allowedMethods(candidates []string) []string {
+	if idx == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var methods []string
+	for _, candidate := range candidates {
+		parts := splitPath(candidate)
+		for _, m := range idx.methods {
+			if seen[m] {
+				continue
+			}
+			for _, rt := range idx.routes[m] {
+				if _, ok := matchSegments(rt.segments, parts); ok {
+					seen[m] = true
+					methods = append(methods, strings.ToUpper(m))
+					break
+				}
+			}
+		}
+	}
+	return methods
+}
+
+// routeCache 缓存最近一次解析的文档与路由表，文档内容不变时直接复用
+type routeCache struct {
+	mu     sync.Mutex
+	src    []byte
+	spec   map[string]interface{}
+	routes *routeIndex
+}
+
+func (c *routeCache) get(spec []byte) (map[string]interface{}, *routeIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.spec == nil || !bytes.Equal(c.src, spec) {
+		c.src, c.spec = spec, parseSpec(spec)
+		c.routes = newRouteIndex(c.spec)
+	}
+	return c.spec, c.routes
+}
+
+// findOperation 宽松匹配：先按已知前缀匹配，失败后逐段去掉开头的路径段再匹配
+// 适用于来源不确定的地址（如粘贴的 cURL 命令）
+func findOperation(spec map[string]interface{}, routes *routeIndex, method, path string, prefixes []string) *operationMatch {
+	if spec == nil {
+		return nil
+	}
+	candidates := pathCandidates(spec, path, prefixes)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		candidates = append(candidates, "/"+strings.Join(segments[i:], "/"))
+	}
+	return routes.matchOperation(method, candidates)
+}
+
+// writeJSON 以 JSON 格式写出响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,176 @@
+package qingfeng
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// builtinThemes 内置主题，顺序即 config.json 中 themes 的顺序
+var builtinThemes = []string{"default", "minimal", "modern"}
+
+// themeRegistry 通过 RegisterTheme 注册的自定义主题
+var themeRegistry struct {
+	mu     sync.Mutex
+	themes map[string]fs.FS
+}
+
+// RegisterTheme registers a custom UI theme for handlers created afterwards
+// 注册自定义 UI 主题（需在创建文档处理器之前调用），与 Config.CustomThemes 同名时以配置为准。
+// fsys 的根目录结构与内置主题相同，缺少的文件使用同名内置主题（不存在时为 default）的文件：
+//
+//	index.html          页面，资源以 ./assets/... 与 app.js 引用
+//	app.js              脚本
+//	assets/css/...      覆盖或新增共享静态资源（CSS、字体、图片）
+//
+// 通常配合 embed 使用：
+//
+//	//go:embed brand
+//	var brandFS embed.FS
+//
+//	sub, _ := fs.Sub(brandFS, "brand")
+//	qingfeng.RegisterTheme("brand", sub)
+func RegisterTheme(name string, fsys fs.FS) {
+	themeRegistry.mu.Lock()
+	defer themeRegistry.mu.Unlock()
+	if themeRegistry.themes == nil {
+		themeRegistry.themes = make(map[string]fs.FS)
+	}
+	themeRegistry.themes[name] = fsys
+}
+
+// customThemes 合并 RegisterTheme 注册的主题与配置中的主题
+func customThemes(cfg Config) map[string]fs.FS {
+	themeRegistry.mu.Lock()
+	defer themeRegistry.mu.Unlock()
+	result := make(map[string]fs.FS, len(themeRegistry.themes)+len(cfg.CustomThemes))
+	for name, fsys := range themeRegistry.themes {
+		result[name] = fsys
+	}
+	for name, fsys := range cfg.CustomThemes {
+		result[name] = fsys
+	}
+	return result
+}
+
+// themeFiles 一个主题的页面、脚本与静态资源：index.html 中的资源地址改写为带内容哈希的 URL
+type themeFiles struct {
+	files map[string]*cachedBody
+	// scriptName 带内容哈希的脚本文件名，如 app.3f2a9c1b.js
+	scriptName string
+	// assets 该主题使用的静态资源，未覆盖任何资源的主题共用内置资源
+	assets       map[string]*cachedBody
+	assetVersion string
+}
+
+// loadThemes 加载内置主题与自定义主题，返回主题表与按顺序排列的主题名称
+// （内置主题在前，自定义主题按名称排序；与内置主题同名的自定义主题替换内置主题）
+func loadThemes(custom map[string]fs.FS) (map[string]*themeFiles, []string) {
+	assetsFS, _ := fs.Sub(uiFS, "ui/assets")
+	assets := loadStaticFiles(assetsFS)
+	assetVersion := staticVersion(assets)
+
+	builtinFS := make(map[string]fs.FS)
+	themes := make(map[string]*themeFiles)
+	names := append([]string(nil), builtinThemes...)
+	for _, name := range builtinThemes {
+		builtinFS[name], _ = fs.Sub(uiFS, "ui/"+name)
+		if _, ok := custom[name]; !ok {
+			themes[name] = loadThemeFiles(builtinFS[name], assets, assetVersion)
+		}
+	}
+
+	customNames := make([]string, 0, len(custom))
+	for name := range custom {
+		customNames = append(customNames, name)
+	}
+	sort.Strings(customNames)
+	for _, name := range customNames {
+		fsys := custom[name]
+		base, ok := builtinFS[name]
+		if !ok {
+			base = builtinFS["default"]
+			names = append(names, name)
+		}
+		themeAssets, themeVersion := assets, assetVersion
+		if _, err := fs.Stat(fsys, "assets"); err == nil {
+			overrides, _ := fs.Sub(fsys, "assets")
+			themeAssets = loadStaticFiles(overlayFS{upper: overrides, lower: assetsFS})
+			themeVersion = staticVersion(themeAssets)
+		}
+		themes[name] = loadThemeFiles(overlayFS{upper: fsys, lower: base}, themeAssets, themeVersion)
+	}
+	return themes, names
+}
+
+// loadThemeFiles 读取主题文件并改写 index.html：
+// ./assets/ 改为 ./assets/<assetVersion>/，app.js 改为 app.<hash>.js
+func loadThemeFiles(fsys fs.FS, assets map[string]*cachedBody, assetVersion string) *themeFiles {
+	t := &themeFiles{files: make(map[string]*cachedBody), assets: assets, assetVersion: assetVersion}
+	for name, file := range loadStaticFiles(fsys) {
+		// 主题目录中的 assets/ 由 assets 提供
+		if !strings.HasPrefix(name, "assets/") {
+			t.files[name] = file
+		}
+	}
+	script, ok := t.files["app.js"]
+	index, hasIndex := t.files["index.html"]
+	if !ok || !hasIndex {
+		return t
+	}
+	t.scriptName = "app." + strings.Trim(script.etag, `"`) + ".js"
+	html := string(index.body)
+	html = strings.ReplaceAll(html, `href="./assets/`, `href="./assets/`+assetVersion+`/`)
+	html = strings.ReplaceAll(html, `src="./assets/`, `src="./assets/`+assetVersion+`/`)
+	html = strings.Replace(html, `<script src="app.js">`, `<script src="`+t.scriptName+`">`, 1)
+	t.files["index.html"] = newCachedBody(index.contentType, []byte(html))
+	return t
+}
+
+// overlayFS 叠加文件系统：upper 中的文件覆盖 lower 中的同名文件，目录内容合并
+type overlayFS struct {
+	upper, lower fs.FS
+}
+
+// Open 实现 fs.FS
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if f, err := o.upper.Open(name); err == nil {
+		return f, nil
+	}
+	if o.staleInLower(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return o.lower.Open(name)
+}
+
+// ReadDir 实现 fs.ReadDirFS，合并两层的目录项
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, upperErr := fs.ReadDir(o.upper, name)
+	lower, lowerErr := fs.ReadDir(o.lower, name)
+	if upperErr != nil && lowerErr != nil {
+		return nil, lowerErr
+	}
+	seen := make(map[string]bool, len(upper))
+	entries := append([]fs.DirEntry(nil), upper...)
+	for _, e := range upper {
+		seen[e.Name()] = true
+	}
+	for _, e := range lower {
+		if !seen[e.Name()] && !o.staleInLower(path.Join(name, e.Name())) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// staleInLower 源文件被 upper 覆盖时，lower 中对应的预压缩文件已过期，不再可见
+func (o overlayFS) staleInLower(name string) bool {
+	if !isPrecompressedFile(name) {
+		return false
+	}
+	_, err := fs.Stat(o.upper, strings.TrimSuffix(name, path.Ext(name)))
+	return err == nil
+}
@@ -12,14 +12,21 @@ let tokenExtractRules = [];
 let environments = [];
 let currentEnvIndex = 0;
 let bodyTemplates = {}; // 请求体模板
+let i18nMessages = {}; // 当前语言的界面文案
+let currentLanguage = 'zh-CN';
 
 // Initialize
 document.addEventListener('DOMContentLoaded', async () => {
     loadThemeFromStorage();
     loadUIThemeFromStorage();
     await loadConfig();
+    await loadPages();
+    loadAuthFromStorage();
     await loadSwagger();
+    setupAuth();
+    loadChangelog();
     setupSearch();
+    setupPageLinks();
     loadGlobalHeadersFromStorage();
     loadTokenExtractRulesFromStorage();
     loadBodyTemplates();
@@ -27,6 +34,57 @@ document.addEventListener('DOMContentLoaded', async () => {
     setupKeyboardShortcuts();
 });
 
+// 界面不使用内联事件属性（onclick 等），以便在不含 'unsafe-inline' 的 CSP 下运行：
+// 元素以 data-onclick、data-onchange、data-oninput 声明调用，如 data-onclick="selectApi('/users', 'get')"，
+// 由 document 上的监听器按冒泡顺序执行；参数支持字符串、数字、布尔值、this（及其属性）与 event
+['click', 'change', 'input'].forEach(type => {
+    document.addEventListener(type, event => {
+        for (let el = event.target; el && el !== document; el = el.parentNode) {
+            const code = el.getAttribute && el.getAttribute('data-on' + type);
+            if (code) runInlineHandler(code, el, event);
+            if (event.cancelBubble) break;
+        }
+    });
+});
+
+// 执行 data-on* 中以分号分隔的函数调用
+function runInlineHandler(code, el, event) {
+    const resolve = path => {
+        const keys = path.split('.');
+        let owner = window;
+        let value = keys[0] === 'this' ? el : keys[0] === 'event' ? event : window[keys[0]];
+        for (const key of keys.slice(1)) {
+            owner = value;
+            value = value == null ? undefined : value[key];
+        }
+        return { owner, value };
+    };
+    const parseArg = token => {
+        token = token.trim();
+        if (token.startsWith("'")) return token.slice(1, -1).replace(/\\(.)/g, '$1');
+        if (/^-?\d+(\.\d+)?$/.test(token)) return Number(token);
+        if (token === 'true' || token === 'false') return token === 'true';
+        if (token === 'null') return null;
+        return resolve(token).value;
+    };
+    for (const statement of code.match(/(?:'(?:[^'\\]|\\.)*'|[^;'])+/g) || []) {
+        const call = statement.trim().match(/^([\w$.]+)\((.*)\)$/s);
+        if (!call) continue;
+        const { owner, value: fn } = resolve(call[1]);
+        if (typeof fn !== 'function') {
+            console.warn('Unknown handler:', call[1]);
+            continue;
+        }
+        const args = (call[2].match(/'(?:[^'\\]|\\.)*'|[^,\s][^,]*/g) || []).map(parseArg);
+        fn.apply(owner, args);
+    }
+}
+
+// 下载链接点击后释放 Blob URL
+function revokeObjectURLLater(url) {
+    setTimeout(() => URL.revokeObjectURL(url), 100);
+}
+
 // Display version in footer
 function displayVersion() {
     const footer = document.querySelector('aside > div:last-child');
@@ -49,6 +107,9 @@ function setupKeyboardShortcuts() {
             closeTokenExtractModal();
             closeThemeModal();
             closeUIThemeModal();
+            closePasteCurlModal();
+            closeChangelogModal();
+            closeAuthModal();
         }
     });
 }
@@ -58,6 +119,7 @@ async function loadConfig() {
     try {
         const res = await fetch('./config.json');
         config = await res.json();
+        await loadLocale();
         document.getElementById('doc-title').textContent = config.title || 'API Docs';
         document.title = config.title || 'API Documentation';
         
@@ -74,6 +136,13 @@ async function loadConfig() {
         // 加载环境配置
         if (config.environments && config.environments.length > 0) {
             environments = config.environments;
+        }
+        // 启用模拟接口时追加 Mock 环境（baseUrl 为 null 表示使用文档中的地址）
+        if (config.mockBaseUrl) {
+            if (environments.length === 0) environments.push({ name: t('common.default'), baseUrl: null });
+            environments = [...environments, { name: 'Mock', baseUrl: config.mockBaseUrl }];
+        }
+        if (environments.length > 0) {
             loadCurrentEnvFromStorage();
             setupEnvironmentSelector();
         }
@@ -93,6 +162,82 @@ async function loadConfig() {
     }
 }
 
+// 加载界面语言包：优先使用 ?lang= 参数，其次是用户保存的选择，最后是服务端按 Accept-Language 协商的语言
+async function loadLocale() {
+    const languages = (config.languages || []).map(l => l.code);
+    const requested = new URLSearchParams(window.location.search).get('lang') || localStorage.getItem('qingfeng_lang');
+    currentLanguage = matchLanguage(requested, languages) || config.language || 'zh-CN';
+    try {
+        const res = await fetch(`./i18n/${encodeURIComponent(currentLanguage)}.json`);
+        if (res.ok) {
+            i18nMessages = await res.json();
+        }
+    } catch (e) {
+        console.log('Using built-in texts');
+    }
+    document.documentElement.lang = currentLanguage;
+    applyI18n();
+    setupLanguageSelector(config.languages || []);
+}
+
+// 在可用语言中查找匹配项（不区分大小写，en-US 可匹配 en）
+function matchLanguage(lang, languages) {
+    if (!lang) return '';
+    const lower = lang.toLowerCase();
+    return languages.find(l => l.toLowerCase() === lower)
+        || languages.find(l => l.toLowerCase().split('-')[0] === lower.split('-')[0])
+        || '';
+}
+
+// 获取界面文案，{name} 占位符替换为 params 中的同名值
+function t(key, params) {
+    let text = i18nMessages[key] ?? key;
+    if (params) {
+        text = text.replace(/\{(\w+)\}/g, (match, name) => params[name] ?? match);
+    }
+    return text;
+}
+
+// 翻译页面中带 data-i18n、data-i18n-title、data-i18n-placeholder 属性的静态文案
+function applyI18n(root = document) {
+    root.querySelectorAll('[data-i18n]').forEach(el => {
+        if (el.dataset.i18n in i18nMessages) el.textContent = i18nMessages[el.dataset.i18n];
+    });
+    root.querySelectorAll('[data-i18n-title]').forEach(el => {
+        if (el.dataset.i18nTitle in i18nMessages) el.title = i18nMessages[el.dataset.i18nTitle];
+    });
+    root.querySelectorAll('[data-i18n-placeholder]').forEach(el => {
+        if (el.dataset.i18nPlaceholder in i18nMessages) el.placeholder = i18nMessages[el.dataset.i18nPlaceholder];
+    });
+}
+
+// 语言选择器（只有一种语言时隐藏）
+function setupLanguageSelector(languages) {
+    const select = document.getElementById('language-select');
+    if (!select || languages.length < 2) return;
+    select.innerHTML = languages.map(l =>
+        `<option value="${escapeHtml(l.code)}"${l.code === currentLanguage ? ' selected' : ''}>${escapeHtml(l.name)}</option>`
+    ).join('');
+    select.classList.remove('hidden');
+}
+
+// 切换界面语言：保存选择后重新加载页面
+function switchLanguage(lang) {
+    localStorage.setItem('qingfeng_lang', lang);
+    const currentUrl = new URL(window.location.href);
+    if (currentUrl.searchParams.has('lang')) {
+        currentUrl.searchParams.set('lang', lang);
+        window.location.href = currentUrl.toString();
+    } else {
+        window.location.reload();
+    }
+}
+
+// 文档相关请求带上当前界面语言，文档中有对应翻译时返回该语言的内容
+function withLanguage(url) {
+    return url + (url.includes('?') ? '&' : '?') + 'lang=' + encodeURIComponent(currentLanguage);
+}
+
 // 设置自定义 Logo
 function setupCustomLogo(logo, link) {
     const titleEl = document.getElementById('doc-title');
@@ -136,14 +281,14 @@ function setupEnvironmentSelector() {
     const envSelector = document.createElement('div');
     envSelector.className = 'env-selector-wrapper';
     envSelector.innerHTML = `
-        <div class="env-selector" onclick="toggleEnvDropdown(event)">
+        <div class="env-selector" data-onclick="toggleEnvDropdown(event)">
             <i class="fas fa-globe"></i>
-            <span id="current-env-name">${environments[currentEnvIndex]?.name || '选择环境'}</span>
+            <span id="current-env-name">${environments[currentEnvIndex]?.name || t('env.select')}</span>
             <i class="fas fa-chevron-down env-arrow"></i>
         </div>
         <div id="env-dropdown" class="env-dropdown hidden">
             ${environments.map((env, i) => `
-                <div class="env-option ${i === currentEnvIndex ? 'active' : ''}" onclick="selectEnvironment(${i})">
+                <div class="env-option ${i === currentEnvIndex ? 'active' : ''}" data-onclick="selectEnvironment(${i})">
                     <i class="fas fa-check env-check"></i>
                     <span>${env.name}</span>
                 </div>
@@ -231,12 +376,12 @@ function selectEnvironment(index) {
     });
     
     closeEnvDropdown();
-    showToast(`已切换到: ${environments[index].name}`);
+    showToast(t('env.switched', { name: environments[index].name }));
 }
 
 // 获取当前环境的 baseUrl
 function getCurrentBaseUrl() {
-    if (environments.length > 0 && environments[currentEnvIndex]) {
+    if (environments.length > 0 && environments[currentEnvIndex] && environments[currentEnvIndex].baseUrl !== null) {
         return environments[currentEnvIndex].baseUrl;
     }
     // 支持 OpenAPI 3.0 的 servers 字段
@@ -276,7 +421,9 @@ function loadCurrentEnvFromStorage() {
 async function loadSwagger() {
     const container = document.getElementById('api-list');
     try {
-        const res = await fetch('./swagger.json');
+        // deref=1：服务端预先展开 $ref，避免大文档在浏览器中反复解析引用
+        // lazyLoad：只加载索引，接口详情在选中时获取
+        const res = await fetch(withLanguage(config.lazyLoad ? './index.json' : './swagger.json?deref=1'));
         if (!res.ok) throw new Error(`HTTP ${res.status}`);
         swaggerData = await res.json();
         renderApiList();
@@ -284,19 +431,34 @@ async function loadSwagger() {
         container.innerHTML = `
             <div class="text-center py-8">
                 <i class="fas fa-exclamation-triangle text-4xl text-yellow-500 mb-3"></i>
-                <p class="text-red-500 font-medium">加载失败</p>
+                <p class="text-red-500 font-medium">${t('sidebar.loadFailed')}</p>
                 <p class="text-sm mt-2" style="color: var(--text-secondary)">
-                    请检查 swagger.json 是否存在<br>
+                    ${t('sidebar.checkSpec')}<br>
                     <code class="text-xs bg-gray-100 dark:bg-gray-800 px-2 py-1 rounded mt-2 inline-block">${e.message}</code>
                 </p>
-                <button onclick="location.reload()" class="mt-4 px-4 py-2 rounded-lg text-sm" style="background: var(--primary); color: white">
-                    <i class="fas fa-redo mr-2"></i>重试
+                <button data-onclick="location.reload()" class="mt-4 px-4 py-2 rounded-lg text-sm" style="background: var(--primary); color: white">
+                    <i class="fas fa-redo mr-2"></i>${t('common.retry')}
                 </button>
             </div>
         `;
     }
 }
 
+// 文档页面（Markdown 指南）
+let guidePages = null;
+let currentPage = null;
+
+async function loadPages() {
+    try {
+        const res = await fetch('./pages.json');
+        if (!res.ok) return;
+        const data = await res.json();
+        if (data.pages?.length) guidePages = data;
+    } catch (e) {
+        console.log('Pages not available');
+    }
+}
+
 // Render API list grouped by tags with multi-level support
 function renderApiList(filter = '') {
     const container = document.getElementById('api-list');
@@ -305,39 +467,145 @@ function renderApiList(filter = '') {
     
     const grouped = {};
     const filterLower = filter.toLowerCase();
+    const matches = filter ? searchMatches : null;
     
     for (const [path, methods] of Object.entries(paths)) {
         for (const [method, api] of Object.entries(methods)) {
             if (method === 'parameters') continue;
             
-            const apiTags = api.tags || ['默认'];
+            const apiTags = api.tags || [t('common.default')];
             const summary = api.summary || '';
             const searchText = `${path} ${summary} ${method}`.toLowerCase();
+            const match = matches?.get(`${method} ${path}`);
             
-            if (filter && !searchText.includes(filterLower)) continue;
+            if (matches ? !match : (filter && !searchText.includes(filterLower))) continue;
             
             for (const tag of apiTags) {
                 if (!grouped[tag]) grouped[tag] = [];
-                grouped[tag].push({ path, method, api });
+                grouped[tag].push({ path, method, api, match });
             }
         }
     }
     
+    // 服务端搜索结果在各分组内按相关度排序
+    if (matches) {
+        for (const apis of Object.values(grouped)) {
+            apis.sort((a, b) => b.match.score - a.match.score);
+        }
+    }
+    
     // Build multi-level tree structure
     const tree = buildTagTree(grouped, tags);
     
     // Render tree
     const html = renderTagTree(tree, 0);
+    const pagesHtml = renderPageList(filter);
     
-    if (!html) {
+    if (!html && !pagesHtml) {
         container.innerHTML = `<p class="text-center py-8" style="color: var(--text-secondary)">
-            ${filter ? '没有找到匹配的接口' : '暂无接口'}
+            ${filter ? t('sidebar.noMatch') : t('sidebar.empty')}
         </p>`;
     } else {
-        container.innerHTML = html;
+        container.innerHTML = pagesHtml + html;
     }
 }
 
+// 文档页面分组，显示在接口分组之上
+function renderPageList(filter = '') {
+    if (!guidePages) return '';
+    const filterLower = filter.toLowerCase();
+    const matches = filter ? searchPageMatches : null;
+    const pages = guidePages.pages
+        .map(page => ({ page, match: matches?.get(page.slug) }))
+        .filter(({ page, match }) => matches ? match : (!filter || `${page.title} ${page.text}`.toLowerCase().includes(filterLower)));
+    if (pages.length === 0) return '';
+    if (matches) pages.sort((a, b) => b.match.score - a.match.score);
+    
+    const groupName = 'qingfeng:pages';
+    const isExpanded = getGroupState(groupName);
+    const items = pages.map(({ page, match }) => `
+        <div class="api-item page-item flex items-center gap-2 px-3 py-2 rounded-lg cursor-pointer text-sm ml-3 ${page.slug === currentPage ? 'active' : ''}" 
+             data-onclick="showPage(this.dataset.page)" data-page="${escapeHtml(page.slug)}">
+            <i class="fas fa-file-alt" style="color: var(--primary)"></i>
+            <span class="truncate flex-1" title="${escapeHtml(page.title)}">${match?.highlights?.summary || escapeHtml(page.title)}</span>
+        </div>
+    `).join('');
+    return `
+        <div class="tag-group mb-1">
+            <div class="px-3 py-2 font-medium flex items-center justify-between cursor-pointer hover:bg-gray-100 dark:hover:bg-gray-800 rounded-lg" 
+                 data-onclick="toggleGroup(this)" data-tag="${groupName}">
+                <span class="flex items-center gap-2">
+                    <i class="fas fa-book" style="color: var(--primary)"></i>
+                    <span>${escapeHtml(guidePages.title || t('pages.title'))}</span>
+                </span>
+                <span class="text-xs px-2 py-0.5 rounded-full" style="background: var(--bg-tertiary)">${pages.length}</span>
+            </div>
+            <div class="tag-apis" style="display: ${isExpanded ? 'block' : 'none'}">
+                ${items}
+            </div>
+        </div>
+    `;
+}
+
+// 显示文档页面，anchor 为页面内标题的 id
+function showPage(slug, anchor) {
+    const page = guidePages?.pages.find(p => p.slug === slug);
+    if (!page) {
+        showToast(t('pages.notFound', { slug }), 'error');
+        return;
+    }
+    currentPage = slug;
+    document.querySelectorAll('.api-item').forEach(el => el.classList.remove('active'));
+    document.querySelector(`.page-item[data-page="${CSS.escape(slug)}"]`)?.classList.add('active');
+    
+    document.getElementById('welcome-panel').classList.add('hidden');
+    document.getElementById('api-detail-panel').classList.add('hidden');
+    document.getElementById('page-panel').classList.remove('hidden');
+    document.getElementById('page-content').innerHTML = page.html;
+    
+    document.getElementById('current-api-info').innerHTML = `
+        <h2 class="text-lg font-semibold">${escapeHtml(page.title)}</h2>
+        <p class="text-sm" style="color: var(--text-secondary)">${escapeHtml(guidePages.title || t('pages.title'))}</p>
+    `;
+    const target = anchor && document.getElementById(anchor);
+    (target || document.getElementById('page-panel')).scrollIntoView();
+}
+
+// 页面中的接口链接（op:）与页面链接
+function setupPageLinks() {
+    document.getElementById('page-content')?.addEventListener('click', (e) => {
+        const link = e.target.closest('a[data-op], a[data-page]');
+        if (!link) return;
+        e.preventDefault();
+        if (link.dataset.page) {
+            showPage(link.dataset.page, link.dataset.anchor);
+            return;
+        }
+        const op = findOperationRef(link.dataset.op);
+        if (op) {
+            selectApi(op.path, op.method);
+        } else {
+            showToast(t('pages.opNotFound', { ref: link.dataset.op }), 'error');
+        }
+    });
+}
+
+// 查找页面链接引用的接口：operationId 或 "方法 路径"
+function findOperationRef(ref) {
+    const paths = swaggerData?.paths || {};
+    const [method, ...rest] = ref.trim().split(/\s+/);
+    const path = rest.join(' ');
+    if (path && paths[path]?.[method.toLowerCase()]) {
+        return { path, method: method.toLowerCase() };
+    }
+    for (const [p, methods] of Object.entries(paths)) {
+        for (const [m, api] of Object.entries(methods)) {
+            if (m !== 'parameters' && api?.operationId === ref) return { path: p, method: m };
+        }
+    }
+    return null;
+}
+
 // Build hierarchical tree from flat tags
 // Tags like "Admin-User", "Admin-Auth" become { Admin: { User: [...], Auth: [...] } }
 function buildTagTree(grouped, tagInfos) {
@@ -392,7 +660,7 @@ function renderTagTree(tree, level) {
             html += `
                 <div class="tag-group mb-1 ${indent}">
                     <div class="px-3 py-2 font-medium flex items-center justify-between cursor-pointer hover:bg-gray-100 dark:hover:bg-gray-800 rounded-lg" 
-                         onclick="toggleGroup(this)" data-tag="${escapeHtml(node._name)}">
+                         data-onclick="toggleGroup(this)" data-tag="${escapeHtml(node._name)}">
                         <span class="flex items-center gap-2">
                             <i class="fas fa-folder text-yellow-500"></i>
                             <span>${escapeHtml(node._displayName)}</span>
@@ -410,7 +678,7 @@ function renderTagTree(tree, level) {
             html += `
                 <div class="tag-group mb-1 ${indent}">
                     <div class="px-3 py-2 font-medium flex items-center justify-between cursor-pointer hover:bg-gray-100 dark:hover:bg-gray-800 rounded-lg" 
-                         onclick="toggleGroup(this)" data-tag="${escapeHtml(node._name)}">
+                         data-onclick="toggleGroup(this)" data-tag="${escapeHtml(node._name)}">
                         <span class="flex items-center gap-2">
                             <i class="fas fa-folder text-yellow-500"></i>
                             <span>${escapeHtml(node._displayName)}</span>
@@ -431,15 +699,20 @@ function renderTagTree(tree, level) {
 // Render API items
 function renderApiItems(apis, level) {
     const indent = level > 0 ? 'ml-3' : '';
-    return apis.map(({ path, method, api }) => {
+    return apis.map(({ path, method, api, match }) => {
         const methodClass = `method-${method.toLowerCase()}`;
         const deprecated = api.deprecated ? 'opacity-50' : '';
+        // 服务端搜索返回已转义的高亮片段，命中的参数与字段显示在提示中
+        const label = (api.summary ? match?.highlights?.summary : match?.highlights?.path) || api.summary || path;
+        const title = [api.summary || path, ...(match?.matches || [])].join('\n');
         return `
             <div class="api-item flex items-center gap-2 px-3 py-2 rounded-lg cursor-pointer text-sm ${deprecated} ${indent}" 
-                 onclick="selectApi('${path}', '${method}')" data-path="${path}" data-method="${method}">
+                 data-onclick="selectApi('${path}', '${method}')" data-path="${path}" data-method="${method}">
                 <span class="${methodClass} px-2 py-0.5 rounded text-white text-xs font-bold uppercase" style="min-width: 50px; text-align: center">${method}</span>
-                <span class="truncate flex-1" title="${api.summary || path}">${api.summary || path}</span>
-                ${api.deprecated ? '<i class="fas fa-ban text-red-400 text-xs" title="已废弃"></i>' : ''}
+                <span class="truncate flex-1" title="${escapeHtml(title)}">${label}</span>
+                ${renderChangeBadge(path, method)}
+                ${renderLockIcon(api)}
+                ${api.deprecated ? '<i class="fas fa-ban text-red-400 text-xs" title="' + t('detail.deprecated') + '"></i>' : ''}
             </div>
         `;
     }).join('');
@@ -487,25 +760,79 @@ function setupSearch() {
     let timeout;
     input.addEventListener('input', (e) => {
         clearTimeout(timeout);
-        timeout = setTimeout(() => renderApiList(e.target.value), 150);
+        timeout = setTimeout(() => runSearch(e.target.value), 150);
     });
 }
 
-function selectApi(path, method) {
+// 服务端全文搜索：大文档、按需加载或使用 method: / tag: / deprecated: 过滤语法时启用，
+// 覆盖描述、参数名与字段名；请求失败时退回本地过滤
+const SERVER_SEARCH_THRESHOLD = 300;
+let searchMatches = null;
+let searchPageMatches = null;
+let searchSeq = 0;
+async function runSearch(query) {
+    const seq = ++searchSeq;
+    let matches = null;
+    let pageMatches = null;
+    if (query.trim() && useServerSearch(query)) {
+        try {
+            const res = await fetch(withLanguage(`./search?q=${encodeURIComponent(query)}&limit=200`));
+            if (!res.ok) throw new Error(`HTTP ${res.status}`);
+            const data = await res.json();
+            matches = new Map(data.results.filter(r => !r.page).map(r => [`${r.method} ${r.path}`, r]));
+            pageMatches = new Map(data.results.filter(r => r.page).map(r => [r.page, r]));
+        } catch (e) {
+            console.warn('服务端搜索失败，使用本地过滤:', e);
+        }
+    }
+    if (seq !== searchSeq) return;
+    searchMatches = matches;
+    searchPageMatches = pageMatches;
+    renderApiList(query);
+}
+
+function useServerSearch(query) {
+    if (config.lazyLoad || /(^|\s)(method|tag|deprecated):/i.test(query)) return true;
+    let count = 0;
+    for (const methods of Object.values(swaggerData?.paths || {})) {
+        count += Object.keys(methods).filter(m => m !== 'parameters').length;
+    }
+    return count > SERVER_SEARCH_THRESHOLD;
+}
+
+// 按需加载模式下获取接口详情（服务端返回 ETag，浏览器会缓存）
+const loadedOperations = new Set();
+async function loadOperationDetail(path, method) {
+    const key = `${method} ${path}`;
+    if (!config.lazyLoad || loadedOperations.has(key)) return;
+    try {
+        const res = await fetch(withLanguage(`./operation?method=${method}&path=${encodeURIComponent(path)}`));
+        if (!res.ok) throw new Error(`HTTP ${res.status}`);
+        swaggerData.paths[path][method] = await res.json();
+        loadedOperations.add(key);
+    } catch (e) {
+        showToast(t('detail.loadFailed', { message: e.message }), 'error');
+    }
+}
+
+async function selectApi(path, method) {
+    await loadOperationDetail(path, method);
     document.querySelectorAll('.api-item').forEach(el => el.classList.remove('active'));
     document.querySelector(`.api-item[data-path="${path}"][data-method="${method}"]`)?.classList.add('active');
     
     const api = swaggerData.paths[path][method];
     currentApi = { path, method, api };
+    currentPage = null;
     
     document.getElementById('welcome-panel').classList.add('hidden');
+    document.getElementById('page-panel').classList.add('hidden');
     document.getElementById('api-detail-panel').classList.remove('hidden');
     
     document.getElementById('detail-method').textContent = method.toUpperCase();
     document.getElementById('detail-method').className = `method-${method} px-3 py-1 rounded text-white text-sm font-bold uppercase`;
     document.getElementById('detail-path').textContent = path;
-    document.getElementById('detail-summary').textContent = api.summary || '未命名接口';
-    document.getElementById('detail-description').textContent = api.description || '暂无描述';
+    document.getElementById('detail-summary').textContent = api.summary || t('detail.unnamed');
+    document.getElementById('detail-description').textContent = api.description || t('detail.noDescription');
     
     const deprecatedEl = document.getElementById('detail-deprecated');
     deprecatedEl.classList.toggle('hidden', !api.deprecated);
@@ -519,6 +846,7 @@ function selectApi(path, method) {
     renderRequestBody(api);
     renderResponseSchema(api);
     renderDebugPanel(api, path);
+    renderAuthRequirements(api);
     
     // 恢复保存的响应结果
     restoreResponse();
@@ -537,7 +865,7 @@ function restoreResponse() {
         document.getElementById('response-time').textContent = time ? `${time}ms` : '';
         document.getElementById('response-content').innerHTML = content;
     } else {
-        document.getElementById('response-content').textContent = '点击"发送请求"查看响应结果';
+        document.getElementById('response-content').textContent = t('response.placeholder');
         document.getElementById('response-info').classList.add('hidden');
     }
 }
@@ -567,7 +895,7 @@ function renderParameters(api) {
             <td class="py-2 px-3 font-mono text-blue-500">${p.name}</td>
             <td class="py-2 px-3"><span class="px-2 py-0.5 rounded text-xs" style="background: var(--bg-tertiary)">${p.in}</span></td>
             <td class="py-2 px-3">${p.type || p.schema?.type || 'object'}</td>
-            <td class="py-2 px-3">${p.required ? '<span class="text-red-500">*必填</span>' : '可选'}</td>
+            <td class="py-2 px-3">${p.required ? '<span class="text-red-500">' + t('common.required') + '</span>' : t('common.optional')}</td>
             <td class="py-2 px-3" style="color: var(--text-secondary)">${p.description || '-'}</td>
         </tr>
     `).join('');
@@ -596,7 +924,7 @@ function renderRequestBody(api) {
         const example = generateExample(schema);
         content.innerHTML = syntaxHighlight(JSON.stringify(example, null, 2));
     } else {
-        content.textContent = '// 请求体结构';
+        content.textContent = '// ' + t('body.structure');
     }
 }
 
@@ -616,10 +944,13 @@ function renderResponseSchema(api) {
     for (const [code, response] of Object.entries(api.responses)) {
         // 支持 OpenAPI 3.0 的 content 格式和 Swagger 2.0 的 schema 格式
         let schema = response.schema;
-        if (!schema && response.content) {
+        let observed = [];
+        if (response.content) {
             // OpenAPI 3.0 格式
             const jsonContent = response.content['application/json'] || response.content['*/*'] || Object.values(response.content)[0];
-            schema = jsonContent?.schema;
+            schema = schema || jsonContent?.schema;
+            // 流量采集得到的真实示例（x-observed）
+            observed = Object.values(jsonContent?.examples || {}).filter(ex => ex && ex['x-observed']);
         }
         const description = response.description || '';
         
@@ -630,17 +961,27 @@ function renderResponseSchema(api) {
                     <span class="text-sm" style="color: var(--text-secondary)">${escapeHtml(description)}</span>
                 </div>
                 <div class="flex gap-2 mb-3">
-                    <button onclick="switchSchemaView(this, 'example', '${code}')" class="schema-tab schema-tab-${code} px-3 py-1 text-sm rounded-lg" style="background: var(--primary); color: white">Example Value</button>
-                    <button onclick="switchSchemaView(this, 'model', '${code}')" class="schema-tab schema-tab-${code} px-3 py-1 text-sm rounded-lg border" style="border-color: var(--border)">Model</button>
+                    <button data-onclick="switchSchemaView(this, 'example', '${code}')" class="schema-tab schema-tab-${code} px-3 py-1 text-sm rounded-lg" style="background: var(--primary); color: white">Example Value</button>
+                    <button data-onclick="switchSchemaView(this, 'model', '${code}')" class="schema-tab schema-tab-${code} px-3 py-1 text-sm rounded-lg border" style="border-color: var(--border)">Model</button>
+                    ${observed.length ? `<button data-onclick="switchSchemaView(this, 'observed', '${code}')" class="schema-tab schema-tab-${code} px-3 py-1 text-sm rounded-lg border" style="border-color: var(--border)">Observed (${observed.length})</button>` : ''}
                 </div>
                 <div id="schema-example-${code}" class="schema-content schema-content-${code}">
-                    <pre class="response-panel rounded-lg p-4 overflow-x-auto text-sm"><code>${schema ? syntaxHighlight(JSON.stringify(generateExample(schema), null, 2)) : '// 无响应体'}</code></pre>
+                    <pre class="response-panel rounded-lg p-4 overflow-x-auto text-sm"><code>${schema ? syntaxHighlight(JSON.stringify(generateExample(schema), null, 2)) : '// ' + t('schema.noBody')}</code></pre>
                 </div>
                 <div id="schema-model-${code}" class="schema-content schema-content-${code} hidden">
                     <div class="rounded-lg p-4 text-sm overflow-x-auto" style="background: var(--bg-tertiary)">
-                        ${schema ? renderSchemaModel(schema) : '<span style="color: var(--text-secondary)">无响应体结构</span>'}
+                        ${schema ? renderSchemaModel(schema) : '<span style="color: var(--text-secondary)">' + t('schema.noStructure') + '</span>'}
                     </div>
                 </div>
+                ${observed.length ? `
+                <div id="schema-observed-${code}" class="schema-content schema-content-${code} hidden space-y-3">
+                    ${observed.map(ex => `
+                        <div>
+                            <div class="text-xs mb-1" style="color: var(--text-secondary)">${escapeHtml(ex.summary || '')}</div>
+                            <pre class="response-panel rounded-lg p-4 overflow-x-auto text-sm"><code>${syntaxHighlight(JSON.stringify(ex.value, null, 2))}</code></pre>
+                        </div>
+                    `).join('')}
+                </div>` : ''}
             </div>
         `;
     }
@@ -670,6 +1011,11 @@ function switchSchemaView(btn, view, code) {
 function renderSchemaModel(schema, depth = 0, parentKey = '') {
     if (depth > 10) return '<span style="color: var(--text-secondary)">...</span>';
     
+    // 服务端展开时输出的循环引用标记
+    if (schema['x-qingfeng-circular']) {
+        return `<span class="text-purple-500" title="${t('schema.circular')}">${escapeHtml(schemaRefName(schema['x-qingfeng-circular']))} <i class="fas fa-redo text-xs"></i></span>`;
+    }
+    
     // 处理 $ref
     if (schema.$ref) {
         const refPath = schema.$ref.replace('#/definitions/', '').replace('#/components/schemas/', '');
@@ -734,7 +1080,7 @@ function renderSchemaModel(schema, depth = 0, parentKey = '') {
                     </div>
                     <div class="flex-1" style="color: var(--text-secondary)">
                         ${description ? `<span>${escapeHtml(description)}</span>` : ''}
-                        ${example !== '' ? `<span class="text-xs ml-2" style="color: var(--text-secondary)">示例: ${escapeHtml(String(example))}</span>` : ''}
+                        ${example !== '' ? `<span class="text-xs ml-2" style="color: var(--text-secondary)">${t('common.example')} ${escapeHtml(String(example))}</span>` : ''}
                     </div>
                 </div>
             `;
@@ -744,7 +1090,7 @@ function renderSchemaModel(schema, depth = 0, parentKey = '') {
                 html += `<div class="ml-4">${renderSchemaModel(prop, depth + 1, key)}</div>`;
             } else if (prop.type === 'array' && prop.items && (prop.items.type === 'object' || prop.items.properties || prop.items.$ref || prop.items.allOf)) {
                 html += `<div class="ml-4 pl-2 border-l" style="border-color: var(--border)">
-                    <div class="text-xs py-1" style="color: var(--text-secondary)">数组元素:</div>
+                    <div class="text-xs py-1" style="color: var(--text-secondary)">${t('schema.arrayItems')}</div>
                     ${renderSchemaModel(prop.items, depth + 1, key)}
                 </div>`;
             }
@@ -758,8 +1104,13 @@ function renderSchemaModel(schema, depth = 0, parentKey = '') {
     return `<span class="text-purple-500">${escapeHtml(schema.type || 'any')}</span>`;
 }
 
+// 引用对应的模型名称
+function schemaRefName(ref) {
+    return ref.replace('#/definitions/', '').replace('#/components/schemas/', '');
+}
+
 function resolveSchema(schema) {
-    if (!schema) return {};
+    if (!schema || schema['x-qingfeng-circular']) return {};
     if (schema.$ref) {
         const refPath = schema.$ref.replace('#/definitions/', '').replace('#/components/schemas/', '');
         return swaggerData.definitions?.[refPath] || swaggerData.components?.schemas?.[refPath] || {};
@@ -785,6 +1136,11 @@ function getSchemaType(schema) {
         const refPath = schema.$ref.replace('#/definitions/', '').replace('#/components/schemas/', '');
         return refPath.split('.').pop();
     }
+    // 服务端展开的 schema 保留了原引用，用于显示模型名称
+    const expandedRef = schema['x-qingfeng-circular'] || schema['x-qingfeng-ref'];
+    if (expandedRef && schema.type !== 'array') {
+        return schemaRefName(expandedRef).split('.').pop();
+    }
     if (schema.type === 'array') {
         if (schema.items) {
             return `${getSchemaType(schema.items)}[]`;
@@ -798,7 +1154,7 @@ function getSchemaType(schema) {
 }
 
 function generateExample(schema, depth = 0) {
-    if (depth > 10) return {};
+    if (depth > 10 || schema['x-qingfeng-circular']) return {};
     
     if (schema.$ref) {
         const refPath = schema.$ref.replace('#/definitions/', '').replace('#/components/schemas/', '');
@@ -887,8 +1243,8 @@ function renderDebugPanel(api, path) {
                 <input type="checkbox" class="mt-2.5 w-4 h-4 cursor-pointer" 
                        data-param-enable="${p.name}" 
                        ${isEnabled ? 'checked' : ''}
-                       onchange="saveParamEnabled('${p.name}', this.checked)"
-                       title="${isEnabled ? '点击禁用此参数' : '点击启用此参数'}">
+                       data-onchange="saveParamEnabled('${p.name}', this.checked)"
+                       title="${isEnabled ? t('params.disable') : t('params.enable')}">
                 <div class="flex-1 ${isEnabled ? '' : 'opacity-50'}">
                     <label class="block text-sm font-medium mb-1">
                         ${p.name} 
@@ -904,23 +1260,23 @@ function renderDebugPanel(api, path) {
                                ${p.description ? `title="${p.description}"` : ''}
                                ${isEnabled ? '' : 'disabled'}
                                multiple
-                               onchange="updateFileList(this)">
+                               data-onchange="updateFileList(this)">
                         <div class="file-list mt-2 text-sm" style="color: var(--text-secondary)"></div>
                     </div>
                     ` : p.enum ? `
                     <select class="input-field w-full rounded-lg px-3 py-2" 
                            data-param="${p.name}" data-in="${p.in}" data-type="${paramType}"
                            ${isEnabled ? '' : 'disabled'}
-                           onchange="saveDebugParam('${p.name}', this.value)">
-                        <option value="">-- 请选择 --</option>
+                           data-onchange="saveDebugParam('${p.name}', this.value)">
+                        <option value="">${t('common.select')}</option>
                         ${p.enum.map(v => `<option value="${escapeHtml(String(v))}" ${String(v) === String(savedValue) ? 'selected' : ''}>${escapeHtml(String(v))}</option>`).join('')}
                     </select>
                     ` : paramType === 'boolean' ? `
                     <select class="input-field w-full rounded-lg px-3 py-2" 
                            data-param="${p.name}" data-in="${p.in}" data-type="${paramType}"
                            ${isEnabled ? '' : 'disabled'}
-                           onchange="saveDebugParam('${p.name}', this.value)">
-                        <option value="">-- 请选择 --</option>
+                           data-onchange="saveDebugParam('${p.name}', this.value)">
+                        <option value="">${t('common.select')}</option>
                         <option value="true" ${savedValue === 'true' ? 'selected' : ''}>true</option>
                         <option value="false" ${savedValue === 'false' ? 'selected' : ''}>false</option>
                     </select>
@@ -930,7 +1286,7 @@ function renderDebugPanel(api, path) {
                            placeholder="${p.description || p.name}"
                            value="${escapeHtml(savedValue)}"
                            ${isEnabled ? '' : 'disabled'}
-                           oninput="saveDebugParam('${p.name}', this.value)">
+                           data-oninput="saveDebugParam('${p.name}', this.value)">
                     `}
                 </div>
             </div>
@@ -972,6 +1328,7 @@ let bodyEditMode = 'form';
 // 完整解析 schema，包括 $ref 和 allOf
 function resolveSchemaFull(schema) {
     if (!schema) return null;
+    if (schema['x-qingfeng-circular']) return { type: 'object', properties: {} };
     
     if (schema.$ref) {
         const refPath = schema.$ref.replace('#/definitions/', '').replace('#/components/schemas/', '');
@@ -1015,7 +1372,7 @@ function resolveSchemaFull(schema) {
 function renderBodyFields(schema, savedValues) {
     const container = document.getElementById('body-fields-container');
     if (!schema || !schema.properties) {
-        container.innerHTML = '<p class="text-sm" style="color: var(--text-secondary)">无结构化字段</p>';
+        container.innerHTML = '<p class="text-sm" style="color: var(--text-secondary)">' + t('body.noFields') + '</p>';
         return;
     }
     
@@ -1024,11 +1381,11 @@ function renderBodyFields(schema, savedValues) {
     
     let html = '<div class="overflow-x-auto"><table class="w-full text-sm">';
     html += `<thead><tr style="border-bottom: 1px solid var(--border)">
-        <th class="text-left py-2 px-2 font-medium">字段名</th>
-        <th class="text-left py-2 px-2 font-medium">类型</th>
-        <th class="text-left py-2 px-2 font-medium">必填</th>
-        <th class="text-left py-2 px-2 font-medium" style="min-width: 200px">值</th>
-        <th class="text-left py-2 px-2 font-medium">说明</th>
+        <th class="text-left py-2 px-2 font-medium">${t('params.fieldName')}</th>
+        <th class="text-left py-2 px-2 font-medium">${t('params.type')}</th>
+        <th class="text-left py-2 px-2 font-medium">${t('params.required')}</th>
+        <th class="text-left py-2 px-2 font-medium" style="min-width: 200px">${t('params.value')}</th>
+        <th class="text-left py-2 px-2 font-medium">${t('params.description')}</th>
     </tr></thead><tbody>`;
     
     for (const [key, prop] of Object.entries(properties)) {
@@ -1047,14 +1404,14 @@ function renderBodyFields(schema, savedValues) {
                 <span class="text-xs px-1.5 py-0.5 rounded" style="background: var(--bg-tertiary)">${escapeHtml(propType)}</span>
             </td>
             <td class="py-2 px-2">
-                ${isRequired ? '<span class="text-red-500 font-medium">*必填</span>' : '<span style="color: var(--text-secondary)">可选</span>'}
+                ${isRequired ? '<span class="text-red-500 font-medium">' + t('common.required') + '</span>' : '<span style="color: var(--text-secondary)">' + t('common.optional') + '</span>'}
             </td>
             <td class="py-2 px-2">
                 ${renderBodyFieldInput(key, prop, savedValue)}
             </td>
             <td class="py-2 px-2" style="color: var(--text-secondary)">
                 ${escapeHtml(description)}
-                ${example !== undefined ? `<br><span class="text-xs">示例: ${escapeHtml(String(example))}</span>` : ''}
+                ${example !== undefined ? `<br><span class="text-xs">${t('common.example')} ${escapeHtml(String(example))}</span>` : ''}
             </td>
         </tr>`;
     }
@@ -1073,14 +1430,14 @@ function renderBodyFieldInput(key, prop, value) {
         const options = prop.enum.map(v => 
             `<option value="${escapeHtml(String(v))}" ${String(v) === String(value) ? 'selected' : ''}>${escapeHtml(String(v))}</option>`
         ).join('');
-        return `<select class="input-field w-full rounded px-2 py-1.5 text-sm" data-body-field="${escapeHtml(key)}" data-type="${type}" onchange="onBodyFieldChange()">
-            <option value="">-- 请选择 --</option>${options}
+        return `<select class="input-field w-full rounded px-2 py-1.5 text-sm" data-body-field="${escapeHtml(key)}" data-type="${type}" data-onchange="onBodyFieldChange()">
+            <option value="">${t('common.select')}</option>${options}
         </select>`;
     }
     
     if (type === 'boolean') {
-        return `<select class="input-field w-full rounded px-2 py-1.5 text-sm" data-body-field="${escapeHtml(key)}" data-type="boolean" onchange="onBodyFieldChange()">
-            <option value="">-- 请选择 --</option>
+        return `<select class="input-field w-full rounded px-2 py-1.5 text-sm" data-body-field="${escapeHtml(key)}" data-type="boolean" data-onchange="onBodyFieldChange()">
+            <option value="">${t('common.select')}</option>
             <option value="true" ${value === true || value === 'true' ? 'selected' : ''}>true</option>
             <option value="false" ${value === false || value === 'false' ? 'selected' : ''}>false</option>
         </select>`;
@@ -1091,14 +1448,14 @@ function renderBodyFieldInput(key, prop, value) {
             data-body-field="${escapeHtml(key)}" data-type="${type}"
             value="${escapedValue}" 
             placeholder="${prop.description || key}"
-            oninput="onBodyFieldChange()">`;
+            data-oninput="onBodyFieldChange()">`;
     }
     
     if (type === 'array' || type === 'object') {
         return `<textarea class="input-field w-full rounded px-2 py-1.5 text-sm font-mono" rows="2"
             data-body-field="${escapeHtml(key)}" data-type="${type}"
             placeholder='${type === 'array' ? '["item1", "item2"]' : '{"key": "value"}'}'
-            oninput="onBodyFieldChange()">${escapedValue}</textarea>`;
+            data-oninput="onBodyFieldChange()">${escapedValue}</textarea>`;
     }
     
     // 默认字符串输入
@@ -1106,7 +1463,7 @@ function renderBodyFieldInput(key, prop, value) {
         data-body-field="${escapeHtml(key)}" data-type="string"
         value="${escapedValue}" 
         placeholder="${prop.description || key}"
-        oninput="onBodyFieldChange()">`;
+        data-oninput="onBodyFieldChange()">`;
 }
 
 // 字段值变化时同步到 JSON
@@ -1178,7 +1535,7 @@ function toggleBodyEditMode() {
         bodyEditMode = 'json';
         formMode.classList.add('hidden');
         jsonMode.classList.remove('hidden');
-        btn.innerHTML = '<i class="fas fa-table mr-1"></i>表单模式';
+        btn.innerHTML = '<i class="fas fa-table mr-1"></i>' + t('body.formMode');
         // 同步表单到 JSON
         syncBodyToJson();
     } else {
@@ -1186,7 +1543,7 @@ function toggleBodyEditMode() {
         bodyEditMode = 'form';
         formMode.classList.remove('hidden');
         jsonMode.classList.add('hidden');
-        btn.innerHTML = '<i class="fas fa-edit mr-1"></i>JSON模式';
+        btn.innerHTML = '<i class="fas fa-edit mr-1"></i>' + t('body.jsonMode');
         // 从 JSON 同步到表单
         syncJsonToFields();
     }
@@ -1299,11 +1656,11 @@ function saveAsTemplate() {
     
     const bodyInput = document.getElementById('debug-body');
     if (!bodyInput || !bodyInput.value.trim()) {
-        showToast('请求体为空', 'error');
+        showToast(t('body.empty'), 'error');
         return;
     }
     
-    const name = prompt('请输入模板名称:', `模板 ${new Date().toLocaleString()}`);
+    const name = prompt(t('template.namePrompt'), t('template.defaultName', { date: new Date().toLocaleString() }));
     if (!name) return;
     
     const key = getTemplateKey(currentApi.path, currentApi.method);
@@ -1316,7 +1673,7 @@ function saveAsTemplate() {
     });
     
     saveBodyTemplates();
-    showToast('模板已保存');
+    showToast(t('template.saved'));
     renderTemplateList();
 }
 
@@ -1329,7 +1686,7 @@ function loadTemplate(index) {
     if (templates[index]) {
         document.getElementById('debug-body').value = templates[index].body;
         saveDebugBody(templates[index].body);
-        showToast(`已加载: ${templates[index].name}`);
+        showToast(t('template.loaded', { name: templates[index].name }));
     }
 }
 
@@ -1341,7 +1698,7 @@ function deleteTemplate(index) {
         bodyTemplates[key].splice(index, 1);
         saveBodyTemplates();
         renderTemplateList();
-        showToast('模板已删除');
+        showToast(t('template.deleted'));
     }
 }
 
@@ -1353,14 +1710,14 @@ function renderTemplateList() {
     const templates = bodyTemplates[key] || [];
     
     if (templates.length === 0) {
-        container.innerHTML = '<div class="text-sm" style="color: var(--text-secondary)">暂无保存的模板</div>';
+        container.innerHTML = '<div class="text-sm" style="color: var(--text-secondary)">' + t('template.empty') + '</div>';
         return;
     }
     
     container.innerHTML = templates.map((t, i) => `
-        <div class="flex items-center justify-between py-1.5 px-2 rounded hover:bg-gray-100 dark:hover:bg-gray-800 cursor-pointer group" onclick="loadTemplate(${i})">
+        <div class="flex items-center justify-between py-1.5 px-2 rounded hover:bg-gray-100 dark:hover:bg-gray-800 cursor-pointer group" data-onclick="loadTemplate(${i})">
             <span class="text-sm truncate flex-1">${escapeHtml(t.name)}</span>
-            <button onclick="event.stopPropagation(); deleteTemplate(${i})" class="text-red-500 opacity-0 group-hover:opacity-100 p-1">
+            <button data-onclick="event.stopPropagation(); deleteTemplate(${i})" class="text-red-500 opacity-0 group-hover:opacity-100 p-1">
                 <i class="fas fa-trash-alt text-xs"></i>
             </button>
         </div>
@@ -1380,7 +1737,7 @@ function toggleTemplateDropdown() {
 // 点击外部关闭下拉框
 document.addEventListener('click', (e) => {
     const dropdown = document.getElementById('template-dropdown');
-    if (dropdown && !e.target.closest('#template-dropdown') && !e.target.closest('[onclick*="toggleTemplateDropdown"]')) {
+    if (dropdown && !e.target.closest('#template-dropdown') && !e.target.closest('[data-onclick*="toggleTemplateDropdown"]')) {
         dropdown.classList.add('hidden');
     }
 });
@@ -1452,11 +1809,11 @@ function renderGlobalHeadersInputs() {
         <div class="flex gap-2 items-center" data-index="${i}">
             <input type="text" class="input-field flex-1 rounded-lg px-3 py-2 text-sm" 
                    placeholder="Header Key" value="${escapeHtml(h.key)}"
-                   onchange="updateGlobalHeader(${i}, 'key', this.value)">
+                   data-onchange="updateGlobalHeader(${i}, 'key', this.value)">
             <input type="text" class="input-field flex-1 rounded-lg px-3 py-2 text-sm" 
                    placeholder="Header Value" value="${escapeHtml(h.value)}"
-                   onchange="updateGlobalHeader(${i}, 'value', this.value)">
-            <button onclick="removeGlobalHeader(${i})" class="p-2 text-red-500 hover:bg-red-50 dark:hover:bg-red-900 rounded-lg">
+                   data-onchange="updateGlobalHeader(${i}, 'value', this.value)">
+            <button data-onclick="removeGlobalHeader(${i})" class="p-2 text-red-500 hover:bg-red-50 dark:hover:bg-red-900 rounded-lg">
                 <i class="fas fa-trash-alt"></i>
             </button>
         </div>
@@ -1481,14 +1838,14 @@ function saveGlobalHeaders() {
     globalHeaders = globalHeaders.filter(h => h.key || h.value);
     const invalidKeys = globalHeaders.filter(h => h.key && !isValidHeaderKey(h.key));
     if (invalidKeys.length > 0) {
-        showToast('Header Key 只能包含英文字符', 'error');
+        showToast(t('globalHeaders.invalidKey'), 'error');
         return;
     }
     saveGlobalHeadersToStorage();
     updateHeadersCount();
     closeGlobalHeadersModal();
     if (currentApi) renderGlobalHeaders();
-    showToast('全局参数已保存');
+    showToast(t('globalHeaders.saved'));
 }
 
 function clearGlobalHeaders() {
@@ -1508,6 +1865,538 @@ function maskValue(key, value) {
     return value;
 }
 
+// ==================== 接口认证（securitySchemes） ====================
+
+let authCredentials = {}; // 安全方案名 → 凭据（apiKey/http: value，basic: username/password，oauth2: accessToken）
+let securitySchemeCache = { data: null, schemes: {} };
+
+// 文档中的安全方案（OpenAPI 3 components.securitySchemes 或 Swagger 2.0 securityDefinitions），统一为 OpenAPI 3 的结构
+function getSecuritySchemes() {
+    if (securitySchemeCache.data !== swaggerData) {
+        const schemes = {};
+        const defined = swaggerData?.components?.securitySchemes || swaggerData?.securityDefinitions || {};
+        for (const [name, scheme] of Object.entries(defined)) {
+            if (scheme && typeof scheme === 'object') schemes[name] = normalizeSecurityScheme(scheme);
+        }
+        securitySchemeCache = { data: swaggerData, schemes };
+    }
+    return securitySchemeCache.schemes;
+}
+
+// Swagger 2.0 的 basic 与 oauth2（flow + authorizationUrl/tokenUrl）转换为 OpenAPI 3 的写法
+function normalizeSecurityScheme(scheme) {
+    if (scheme.type === 'basic') return { ...scheme, type: 'http', scheme: 'basic' };
+    if (scheme.type === 'oauth2' && !scheme.flows) {
+        const flowNames = { implicit: 'implicit', password: 'password', application: 'clientCredentials', accessCode: 'authorizationCode' };
+        const flow = { authorizationUrl: scheme.authorizationUrl, tokenUrl: scheme.tokenUrl, scopes: scheme.scopes || {} };
+        return { ...scheme, flows: { [flowNames[scheme.flow] || scheme.flow]: flow } };
+    }
+    return scheme;
+}
+
+function isBasicScheme(scheme) {
+    return scheme?.type === 'http' && (scheme.scheme || '').toLowerCase() === 'basic';
+}
+
+// 接口的安全要求：接口的 security 优先于文档顶层的 security；满足任意一项即可，空对象表示认证可选
+function getSecurityRequirements(api) {
+    const requirements = api?.security ?? swaggerData?.security ?? [];
+    return Array.isArray(requirements) ? requirements : [];
+}
+
+function isSecured(api) {
+    return getSecurityRequirements(api).some(req => Object.keys(req).length > 0);
+}
+
+function hasCredentials(name) {
+    const scheme = getSecuritySchemes()[name];
+    const credential = authCredentials[name];
+    if (!scheme || !credential) return false;
+    if (isBasicScheme(scheme)) return !!credential.username;
+    if (scheme.type === 'oauth2' || scheme.type === 'openIdConnect') return !!credential.accessToken;
+    return !!credential.value;
+}
+
+// 返回所有方案都已填写凭据的安全要求，没有时返回 null
+function authorizedRequirement(api) {
+    return getSecurityRequirements(api).find(req => Object.keys(req).length > 0 && Object.keys(req).every(hasCredentials)) || null;
+}
+
+// 计算接口需要附加的认证信息，只有接口声明了对应的安全方案时才附加
+function buildAuthParams(api) {
+    const result = { headers: {}, query: {}, cookies: {} };
+    const requirement = authorizedRequirement(api);
+    if (!requirement) return result;
+    const schemes = getSecuritySchemes();
+    for (const name of Object.keys(requirement)) {
+        const scheme = schemes[name];
+        const credential = authCredentials[name];
+        if (scheme.type === 'apiKey') {
+            const target = scheme.in === 'query' ? result.query : scheme.in === 'cookie' ? result.cookies : result.headers;
+            target[scheme.name] = credential.value;
+        } else if (isBasicScheme(scheme)) {
+            result.headers['Authorization'] = 'Basic ' + btoa(unescape(encodeURIComponent(`${credential.username}:${credential.password || ''}`)));
+        } else if (scheme.type === 'http') {
+            const type = (scheme.scheme || 'bearer').toLowerCase() === 'bearer' ? 'Bearer' : scheme.scheme;
+            result.headers['Authorization'] = `${type} ${credential.value}`;
+        } else {
+            // oauth2、openIdConnect
+            result.headers['Authorization'] = `Bearer ${credential.accessToken}`;
+        }
+    }
+    return result;
+}
+
+function describeSecurityScheme(scheme) {
+    if (!scheme) return t('auth.unknownScheme');
+    switch (scheme.type) {
+        case 'apiKey':
+            return `API Key (${scheme.in}: ${scheme.name})`;
+        case 'http':
+            if (isBasicScheme(scheme)) return 'HTTP Basic';
+            return `HTTP ${(scheme.scheme || '').toLowerCase() === 'bearer' ? 'Bearer' : scheme.scheme}${scheme.bearerFormat ? ` (${scheme.bearerFormat})` : ''}`;
+        case 'oauth2':
+            return `OAuth2 (${Object.keys(scheme.flows || {}).join(', ')})`;
+        case 'openIdConnect':
+            return 'OpenID Connect';
+        default:
+            return scheme.type || '';
+    }
+}
+
+// 侧边栏的锁图标：已填写凭据时为锁定状态
+function renderLockIcon(api) {
+    if (!isSecured(api)) return '';
+    const names = getSecurityRequirements(api).map(req => Object.keys(req).join(' + ')).filter(Boolean).join(' / ');
+    const authorized = !!authorizedRequirement(api);
+    return `<i class="fas ${authorized ? 'fa-lock' : 'fa-unlock'} text-xs" style="color: ${authorized ? 'var(--primary)' : 'var(--text-secondary)'}" title="${escapeHtml(t(authorized ? 'auth.authorized' : 'auth.required', { schemes: names }))}"></i>`;
+}
+
+// 调试面板中显示接口的认证要求
+function renderAuthRequirements(api) {
+    const container = document.getElementById('debug-auth-container');
+    const list = document.getElementById('debug-auth-list');
+    if (!container || !list) return;
+    const all = getSecurityRequirements(api);
+    const requirements = all.filter(req => Object.keys(req).length > 0);
+    if (requirements.length === 0) {
+        container.classList.add('hidden');
+        return;
+    }
+    container.classList.remove('hidden');
+    const schemes = getSecuritySchemes();
+    list.innerHTML = requirements.map(req => `
+        <div class="flex items-center gap-2 py-1 flex-wrap">
+            ${Object.entries(req).map(([name, scopes]) => `
+                <span class="inline-flex items-center gap-1">
+                    <i class="fas ${hasCredentials(name) ? 'fa-lock' : 'fa-unlock'}" style="color: ${hasCredentials(name) ? 'var(--primary)' : 'var(--text-secondary)'}"></i>
+                    <span class="font-mono">${escapeHtml(name)}</span>
+                    <span class="text-xs" style="color: var(--text-secondary)">${escapeHtml(describeSecurityScheme(schemes[name]))}${scopes?.length ? ' · ' + escapeHtml(scopes.join(', ')) : ''}</span>
+                </span>
+            `).join('<span style="color: var(--text-secondary)">+</span>')}
+        </div>
+    `).join('') + `
+        ${all.length > requirements.length ? `<p class="text-xs mt-1" style="color: var(--text-secondary)">${t('auth.optional')}</p>` : ''}
+        <button data-onclick="openAuthModal()" class="mt-2 text-xs px-2 py-1 rounded border hover:bg-gray-100 dark:hover:bg-gray-700" style="border-color: var(--border)">
+            <i class="fas fa-key mr-1"></i>${t('auth.authorize')}
+        </button>
+    `;
+}
+
+function loadAuthFromStorage() {
+    try {
+        authCredentials = JSON.parse(localStorage.getItem('qingfeng_auth') || '{}');
+    } catch (e) {
+        authCredentials = {};
+    }
+}
+
+function saveAuthToStorage() {
+    try {
+        localStorage.setItem('qingfeng_auth', JSON.stringify(authCredentials));
+    } catch (e) {}
+}
+
+// 文档中有安全方案时显示认证按钮
+function setupAuth() {
+    const hasSchemes = Object.keys(getSecuritySchemes()).length > 0;
+    document.getElementById('authorize-btn')?.classList.toggle('hidden', !hasSchemes);
+    document.getElementById('mobile-authorize-btn')?.classList.toggle('hidden', !hasSchemes);
+    updateAuthCount();
+    setupOAuthCallback();
+    Object.keys(authCredentials).forEach(scheduleTokenRefresh);
+}
+
+function updateAuthCount() {
+    const badge = document.getElementById('authorize-count');
+    if (!badge) return;
+    const count = Object.keys(getSecuritySchemes()).filter(hasCredentials).length;
+    badge.textContent = count;
+    badge.classList.toggle('hidden', count === 0);
+}
+
+function openAuthModal() {
+    document.getElementById('auth-modal').classList.remove('hidden');
+    renderAuthSchemes();
+}
+
+function closeAuthModal() {
+    document.getElementById('auth-modal').classList.add('hidden');
+}
+
+function renderAuthSchemes() {
+    const container = document.getElementById('auth-schemes');
+    const entries = Object.entries(getSecuritySchemes());
+    if (entries.length === 0) {
+        container.innerHTML = `<p class="text-sm text-center py-4" style="color: var(--text-secondary)">${t('auth.noSchemes')}</p>`;
+        return;
+    }
+    container.innerHTML = entries.map(([name, scheme]) => `
+        <div class="rounded-lg p-3" style="border: 1px solid var(--border)">
+            <div class="flex items-center justify-between gap-2">
+                <span class="font-medium flex items-center gap-2">
+                    <i class="fas ${hasCredentials(name) ? 'fa-lock' : 'fa-unlock'}" style="color: ${hasCredentials(name) ? 'var(--primary)' : 'var(--text-secondary)'}"></i>${escapeHtml(name)}
+                </span>
+                <span class="text-xs" style="color: var(--text-secondary)">${escapeHtml(describeSecurityScheme(scheme))}</span>
+            </div>
+            ${scheme.description ? `<p class="text-xs mt-1" style="color: var(--text-secondary)">${escapeHtml(scheme.description)}</p>` : ''}
+            ${renderAuthInputs(name, scheme)}
+        </div>
+    `).join('');
+}
+
+function renderAuthInputs(name, scheme) {
+    const credential = authCredentials[name] || {};
+    const input = (field, placeholder, type = 'text') => `
+        <input type="${type}" class="input-field w-full rounded-lg px-3 py-2 text-sm mt-2" autocomplete="off"
+               data-auth-scheme="${escapeHtml(name)}" data-auth-field="${field}"
+               placeholder="${escapeHtml(placeholder)}" value="${escapeHtml(credential[field] || '')}">`;
+    if (isBasicScheme(scheme)) {
+        return input('username', t('auth.username')) + input('password', t('auth.password'), 'password');
+    }
+    if (scheme.type === 'oauth2' || scheme.type === 'openIdConnect') {
+        // 可以按 OAuth2 授权模式获取令牌，也可以直接填写
+        return (scheme.type === 'oauth2' ? renderOAuthFlows(name, scheme) : '') + input('accessToken', 'Access Token', 'password');
+    }
+    if (scheme.type === 'apiKey') {
+        const hint = scheme.in === 'cookie' ? `<p class="text-xs mt-1" style="color: var(--text-secondary)">${t('auth.cookieHint')}</p>` : '';
+        return input('value', scheme.name || 'API Key', 'password') + hint;
+    }
+    return input('value', 'Token', 'password');
+}
+
+function saveAuth() {
+    document.querySelectorAll('#auth-schemes [data-auth-field]').forEach(input => {
+        const name = input.dataset.authScheme;
+        const field = input.dataset.authField;
+        const value = input.value.trim();
+        const credential = { ...(authCredentials[name] || {}), [field]: value };
+        // 手动修改的令牌不再按原来的有效期自动刷新
+        if (field === 'accessToken' && value !== authCredentials[name]?.accessToken) {
+            delete credential.refreshToken;
+            delete credential.expiresAt;
+        }
+        authCredentials[name] = credential;
+    });
+    for (const name of Object.keys(authCredentials)) {
+        if (!hasCredentials(name)) delete authCredentials[name];
+    }
+    new Set([...Object.keys(authCredentials), ...Object.keys(tokenRefreshTimers)]).forEach(scheduleTokenRefresh);
+    saveAuthToStorage();
+    updateAuthCount();
+    closeAuthModal();
+    refreshAuthState();
+    showToast(t('auth.saved'));
+}
+
+function clearAuth() {
+    authCredentials = {};
+    Object.values(tokenRefreshTimers).forEach(clearTimeout);
+    tokenRefreshTimers = {};
+    saveAuthToStorage();
+    updateAuthCount();
+    renderAuthSchemes();
+    refreshAuthState();
+}
+
+// 凭据变化后刷新侧边栏的锁图标与调试面板
+function refreshAuthState() {
+    if (swaggerData) renderApiList(document.getElementById('search-input')?.value || '');
+    if (currentApi) renderAuthRequirements(currentApi.api);
+}
+
+// ==================== OAuth2 授权流程 ====================
+
+const OAUTH_FLOWS = ['authorizationCode', 'implicit', 'password', 'clientCredentials'];
+const TOKEN_REFRESH_MARGIN = 30 * 1000; // 令牌过期前 30 秒刷新
+let oauthPending = null; // 等待回调的授权请求（授权码与隐式模式）
+let tokenRefreshTimers = {};
+let tokenRefreshes = {}; // 进行中的刷新请求，避免并发重复刷新
+
+// OAuth2 方案的各个授权模式：客户端信息、权限范围与授权按钮
+function renderOAuthFlows(name, scheme) {
+    const credential = authCredentials[name] || {};
+    const defaults = config.oauth2 || {};
+    const flows = Object.entries(scheme.flows || {}).filter(([flowName, flow]) => OAUTH_FLOWS.includes(flowName) && flow);
+    const input = (field, placeholder, value, type = 'text') => `
+        <input type="${type}" class="input-field w-full rounded-lg px-3 py-2 text-sm mt-2" autocomplete="off"
+               data-oauth-field="${field}" placeholder="${escapeHtml(placeholder)}" value="${escapeHtml(value || '')}">`;
+    return flows.map(([flowName, flow]) => {
+        const scopes = Object.entries(flow.scopes || {});
+        const selected = credential.flow === flowName ? credential.scopes : (defaults.scopes?.length ? defaults.scopes : null);
+        return `
+            <div class="rounded-lg p-2 mt-2" style="background: var(--bg-secondary)" data-auth-scheme="${escapeHtml(name)}" data-oauth-flow="${flowName}">
+                <div class="text-xs font-medium">${t('auth.flow.' + flowName)}</div>
+                <div class="text-xs font-mono break-all mt-1" style="color: var(--text-secondary)">
+                    ${flow.authorizationUrl ? `<div>authorizationUrl: ${escapeHtml(flow.authorizationUrl)}</div>` : ''}
+                    ${flow.tokenUrl ? `<div>tokenUrl: ${escapeHtml(flow.tokenUrl)}</div>` : ''}
+                    ${flow.refreshUrl ? `<div>refreshUrl: ${escapeHtml(flow.refreshUrl)}</div>` : ''}
+                </div>
+                ${input('clientId', 'client_id', credential.clientId || defaults.clientId)}
+                ${flowName !== 'implicit' ? input('clientSecret', t('auth.clientSecret'), credential.clientSecret || defaults.clientSecret, 'password') : ''}
+                ${flowName === 'password' ? input('username', t('auth.username'), credential.username) + input('password', t('auth.password'), '', 'password') : ''}
+                ${scopes.length ? `
+                    <div class="text-xs mt-2" style="color: var(--text-secondary)">${t('auth.scopes')}</div>
+                    ${scopes.map(([scope, desc]) => `
+                        <label class="flex items-center gap-2 text-xs mt-1">
+                            <input type="checkbox" data-oauth-scope value="${escapeHtml(scope)}" ${!selected || selected.includes(scope) ? 'checked' : ''}>
+                            <span class="font-mono">${escapeHtml(scope)}</span>
+                            ${desc ? `<span style="color: var(--text-secondary)">${escapeHtml(desc)}</span>` : ''}
+                        </label>
+                    `).join('')}
+                ` : ''}
+                <button data-onclick="authorizeOAuth(this)" class="btn-primary mt-2 px-3 py-1 rounded text-xs">
+                    <i class="fas fa-sign-in-alt mr-1"></i>${t('auth.authorize')}
+                </button>
+            </div>
+        `;
+    }).join('') + renderOAuthTokenStatus(credential);
+}
+
+// 已获取令牌的有效期与自动刷新状态
+function renderOAuthTokenStatus(credential) {
+    if (!credential.accessToken || !credential.expiresAt) return '';
+    const expired = credential.expiresAt <= Date.now();
+    const text = expired
+        ? t('auth.tokenExpired')
+        : t('auth.tokenExpires', { time: new Date(credential.expiresAt).toLocaleTimeString() });
+    return `
+        <p class="text-xs mt-2" style="color: ${expired && !credential.refreshToken ? '#ef4444' : 'var(--text-secondary)'}">
+            <i class="fas fa-clock mr-1"></i>${escapeHtml(text)}${credential.refreshToken ? ` · ${t('auth.autoRefresh')}` : ''}
+        </p>
+    `;
+}
+
+// 点击授权按钮：授权码与隐式模式打开授权页面，密码与客户端凭据模式直接请求令牌
+async function authorizeOAuth(button) {
+    const box = button.closest('[data-oauth-flow]');
+    const name = box.dataset.authScheme;
+    const flowName = box.dataset.oauthFlow;
+    const flow = getSecuritySchemes()[name]?.flows?.[flowName];
+    if (!flow) return;
+    const field = key => box.querySelector(`[data-oauth-field="${key}"]`)?.value.trim() || '';
+    const settings = {
+        flow: flowName,
+        clientId: field('clientId'),
+        clientSecret: field('clientSecret'),
+        scopes: [...box.querySelectorAll('[data-oauth-scope]:checked')].map(el => el.value)
+    };
+    try {
+        if (flowName === 'authorizationCode' || flowName === 'implicit') {
+            await startOAuthRedirect(name, flow, settings);
+            return;
+        }
+        const params = { grant_type: flowName === 'password' ? 'password' : 'client_credentials' };
+        if (flowName === 'password') {
+            settings.username = field('username');
+            params.username = settings.username;
+            params.password = field('password');
+        }
+        if (settings.scopes.length) params.scope = settings.scopes.join(' ');
+        storeOAuthToken(name, settings, await requestOAuthToken(flow.tokenUrl, params, settings));
+    } catch (e) {
+        showToast(t('auth.oauthFailed', { error: e.message }), 'error');
+    }
+}
+
+// 回调地址：配置的 RedirectURL，默认为文档页面所在路径下的 oauth2-redirect
+function getOAuthRedirectUrl() {
+    return config.oauth2?.redirectUrl || new URL('./oauth2-redirect', location.href).href;
+}
+
+// 文档中的相对地址相对于当前环境的接口地址
+function resolveOAuthUrl(url) {
+    return new URL(url, new URL(getCurrentBaseUrl() || '/', location.href));
+}
+
+function randomOAuthString(bytes) {
+    const data = crypto.getRandomValues(new Uint8Array(bytes));
+    return base64UrlEncode(data);
+}
+
+function base64UrlEncode(data) {
+    return btoa(String.fromCharCode(...data)).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+// PKCE：code_challenge = BASE64URL(SHA256(code_verifier))；非安全上下文（无 crypto.subtle）时使用 plain
+async function pkceChallenge(verifier) {
+    if (!window.crypto?.subtle) return { challenge: verifier, method: 'plain' };
+    const digest = await crypto.subtle.digest('SHA-256', new TextEncoder().encode(verifier));
+    return { challenge: base64UrlEncode(new Uint8Array(digest)), method: 'S256' };
+}
+
+async function startOAuthRedirect(name, flow, settings) {
+    // 先打开窗口，避免异步计算后被浏览器拦截弹窗
+    const popup = window.open('', 'qingfeng-oauth2', 'width=600,height=700');
+    if (!popup) throw new Error(t('auth.popupBlocked'));
+    try {
+        const state = randomOAuthString(16);
+        const redirectUri = getOAuthRedirectUrl();
+        const url = resolveOAuthUrl(flow.authorizationUrl);
+        const params = {
+            response_type: settings.flow === 'implicit' ? 'token' : 'code',
+            client_id: settings.clientId,
+            redirect_uri: redirectUri,
+            state
+        };
+        if (settings.scopes.length) params.scope = settings.scopes.join(' ');
+        const pending = { name, settings, state, redirectUri, tokenUrl: flow.tokenUrl };
+        if (settings.flow === 'authorizationCode') {
+            pending.verifier = randomOAuthString(48);
+            const { challenge, method } = await pkceChallenge(pending.verifier);
+            params.code_challenge = challenge;
+            params.code_challenge_method = method;
+        }
+        Object.entries({ ...(config.oauth2?.additionalParams || {}), ...params }).forEach(([key, value]) => url.searchParams.set(key, value));
+        oauthPending = pending;
+        popup.location.href = url.href;
+    } catch (e) {
+        popup.close();
+        throw e;
+    }
+}
+
+// 接收回调页面传回的参数（postMessage，或打开者不可用时经 localStorage）
+function setupOAuthCallback() {
+    window.addEventListener('message', e => {
+        if (e.origin === location.origin && e.data?.type === 'qingfeng-oauth2') handleOAuthCallback(e.data.params || {});
+    });
+    window.addEventListener('storage', e => {
+        if (e.key !== 'qingfeng_oauth2_callback' || !e.newValue) return;
+        localStorage.removeItem('qingfeng_oauth2_callback');
+        try {
+            handleOAuthCallback(JSON.parse(e.newValue).params || {});
+        } catch (err) {}
+    });
+}
+
+async function handleOAuthCallback(params) {
+    const pending = oauthPending;
+    // state 不匹配的回调不予处理（防止 CSRF）
+    if (!pending || params.state !== pending.state) return;
+    oauthPending = null;
+    try {
+        if (params.error) throw new Error(params.error_description || params.error);
+        if (pending.settings.flow === 'implicit') {
+            storeOAuthToken(pending.name, pending.settings, params);
+            return;
+        }
+        const token = await requestOAuthToken(pending.tokenUrl, {
+            grant_type: 'authorization_code',
+            code: params.code,
+            redirect_uri: pending.redirectUri,
+            code_verifier: pending.verifier
+        }, pending.settings);
+        storeOAuthToken(pending.name, pending.settings, token);
+    } catch (e) {
+        showToast(t('auth.oauthFailed', { error: e.message }), 'error');
+    }
+}
+
+// 请求令牌端点：有客户端密钥时使用 HTTP Basic 认证客户端，否则在请求体中携带 client_id
+async function requestOAuthToken(tokenUrl, params, settings) {
+    if (!tokenUrl) throw new Error(t('auth.noTokenUrl'));
+    const body = new URLSearchParams(params);
+    const headers = { 'Content-Type': 'application/x-www-form-urlencoded', 'Accept': 'application/json' };
+    if (settings.clientSecret) {
+        headers['Authorization'] = 'Basic ' + btoa(`${encodeURIComponent(settings.clientId)}:${encodeURIComponent(settings.clientSecret)}`);
+    } else if (settings.clientId) {
+        body.set('client_id', settings.clientId);
+    }
+    // 跨域的令牌端点可经文档服务转发（需启用 OAuth2.ProxyTokenRequests）
+    const url = resolveOAuthUrl(tokenUrl);
+    const target = config.oauth2?.proxy && /^https?:\/\//i.test(tokenUrl) && url.origin !== location.origin
+        ? `./oauth2/token?url=${encodeURIComponent(tokenUrl)}`
+        : url.href;
+    const res = await fetch(target, { method: 'POST', headers, body });
+    const text = await res.text();
+    let data;
+    try {
+        data = JSON.parse(text);
+    } catch (e) {
+        data = Object.fromEntries(new URLSearchParams(text));
+    }
+    if (!res.ok || data.error) throw new Error(data.error_description || data.error || `HTTP ${res.status}`);
+    if (!data.access_token) throw new Error(t('auth.noAccessToken'));
+    return data;
+}
+
+// 保存令牌；刷新响应没有返回新的 refresh_token 时沿用原来的
+function storeOAuthToken(name, settings, token, silent = false) {
+    const { flow, clientId, clientSecret, scopes, username } = settings;
+    authCredentials[name] = {
+        flow, clientId, clientSecret, scopes, username,
+        accessToken: token.access_token,
+        refreshToken: token.refresh_token || (silent ? authCredentials[name]?.refreshToken : '') || '',
+        expiresAt: Number(token.expires_in) > 0 ? Date.now() + Number(token.expires_in) * 1000 : 0
+    };
+    saveAuthToStorage();
+    scheduleTokenRefresh(name);
+    updateAuthCount();
+    refreshAuthState();
+    if (!document.getElementById('auth-modal')?.classList.contains('hidden')) renderAuthSchemes();
+    if (!silent) showToast(t('auth.oauthSuccess', { name }));
+}
+
+// 在令牌过期前自动刷新（需要授权服务器返回 refresh_token 与 expires_in）
+function scheduleTokenRefresh(name) {
+    clearTimeout(tokenRefreshTimers[name]);
+    delete tokenRefreshTimers[name];
+    const credential = authCredentials[name];
+    if (!credential?.refreshToken || !credential.expiresAt) return;
+    const delay = Math.min(Math.max(credential.expiresAt - Date.now() - TOKEN_REFRESH_MARGIN, 0), 0x7fffffff);
+    tokenRefreshTimers[name] = setTimeout(() => {
+        refreshOAuthToken(name).catch(e => showToast(t('auth.refreshFailed', { name, error: e.message }), 'error'));
+    }, delay);
+}
+
+function refreshOAuthToken(name) {
+    if (!tokenRefreshes[name]) {
+        tokenRefreshes[name] = (async () => {
+            const credential = authCredentials[name];
+            const flow = getSecuritySchemes()[name]?.flows?.[credential?.flow];
+            if (!credential?.refreshToken || !flow) throw new Error(t('auth.noTokenUrl'));
+            const params = { grant_type: 'refresh_token', refresh_token: credential.refreshToken };
+            const token = await requestOAuthToken(flow.refreshUrl || flow.tokenUrl, params, credential);
+            storeOAuthToken(name, credential, token, true);
+        })().finally(() => delete tokenRefreshes[name]);
+    }
+    return tokenRefreshes[name];
+}
+
+// 发送请求前刷新接口所需的、即将过期的令牌（页面休眠后定时器可能未按时触发）
+async function refreshExpiringTokens(api) {
+    const requirement = authorizedRequirement(api);
+    if (!requirement) return;
+    const expiring = Object.keys(requirement).filter(name => {
+        const credential = authCredentials[name];
+        return credential?.refreshToken && credential.expiresAt && credential.expiresAt - Date.now() < TOKEN_REFRESH_MARGIN;
+    });
+    await Promise.all(expiring.map(name => refreshOAuthToken(name).catch(e => {
+        showToast(t('auth.refreshFailed', { name, error: e.message }), 'error');
+    })));
+}
+
 // 更新文件列表显示
 function updateFileList(input) {
     const fileList = input.parentElement.querySelector('.file-list');
@@ -1522,7 +2411,7 @@ function updateFileList(input) {
     if (files.length === 1) {
         fileList.innerHTML = `<div class="flex items-center gap-2"><i class="fas fa-file text-blue-500"></i>${escapeHtml(files[0].name)} <span class="text-xs">(${formatFileSize(files[0].size)})</span></div>`;
     } else {
-        let html = `<div class="mb-1"><i class="fas fa-files text-blue-500 mr-1"></i>已选择 ${files.length} 个文件:</div><ul class="ml-4 space-y-1">`;
+        let html = `<div class="mb-1"><i class="fas fa-files text-blue-500 mr-1"></i>${t('debug.filesSelected', { count: files.length })}</div><ul class="ml-4 space-y-1">`;
         for (let i = 0; i < files.length; i++) {
             html += `<li class="flex items-center gap-2"><i class="fas fa-file-alt text-gray-400"></i>${escapeHtml(files[i].name)} <span class="text-xs">(${formatFileSize(files[i].size)})</span></li>`;
         }
@@ -1597,32 +2486,32 @@ function renderTokenExtractRules() {
     container.innerHTML = tokenExtractRules.map((r, i) => `
         <div class="p-3 rounded-lg mb-2" style="background: var(--bg-tertiary)">
             <div class="flex items-center gap-2 mb-2">
-                <input type="checkbox" ${r.enabled ? 'checked' : ''} onchange="updateTokenRule(${i}, 'enabled', this.checked)" class="w-4 h-4">
-                <span class="text-sm font-medium">规则 ${i + 1}</span>
-                <button onclick="removeTokenRule(${i})" class="ml-auto p-1 text-red-500 hover:bg-red-50 dark:hover:bg-red-900 rounded">
+                <input type="checkbox" ${r.enabled ? 'checked' : ''} data-onchange="updateTokenRule(${i}, 'enabled', this.checked)" class="w-4 h-4">
+                <span class="text-sm font-medium">${t('token.rule', { index: i + 1 })}</span>
+                <button data-onclick="removeTokenRule(${i})" class="ml-auto p-1 text-red-500 hover:bg-red-50 dark:hover:bg-red-900 rounded">
                     <i class="fas fa-trash-alt text-xs"></i>
                 </button>
             </div>
             <div class="grid grid-cols-2 gap-2">
                 <div class="col-span-2">
-                    <label class="block text-xs mb-1" style="color: var(--text-secondary)">接口路径 (支持 * 通配符)</label>
+                    <label class="block text-xs mb-1" style="color: var(--text-secondary)">${t('token.pathLabel')}</label>
                     <input type="text" class="input-field w-full rounded px-2 py-1 text-sm" placeholder="*/login" 
-                           value="${escapeHtml(r.pathPattern || '')}" onchange="updateTokenRule(${i}, 'pathPattern', this.value)">
+                           value="${escapeHtml(r.pathPattern || '')}" data-onchange="updateTokenRule(${i}, 'pathPattern', this.value)">
                 </div>
                 <div>
-                    <label class="block text-xs mb-1" style="color: var(--text-secondary)">JSON 路径</label>
+                    <label class="block text-xs mb-1" style="color: var(--text-secondary)">${t('token.jsonPath')}</label>
                     <input type="text" class="input-field w-full rounded px-2 py-1 text-sm" placeholder="data.token" 
-                           value="${escapeHtml(r.jsonPath)}" onchange="updateTokenRule(${i}, 'jsonPath', this.value)">
+                           value="${escapeHtml(r.jsonPath)}" data-onchange="updateTokenRule(${i}, 'jsonPath', this.value)">
                 </div>
                 <div>
                     <label class="block text-xs mb-1" style="color: var(--text-secondary)">Header Key</label>
                     <input type="text" class="input-field w-full rounded px-2 py-1 text-sm" placeholder="Authorization" 
-                           value="${escapeHtml(r.headerKey)}" onchange="updateTokenRule(${i}, 'headerKey', this.value)">
+                           value="${escapeHtml(r.headerKey)}" data-onchange="updateTokenRule(${i}, 'headerKey', this.value)">
                 </div>
                 <div class="col-span-2">
-                    <label class="block text-xs mb-1" style="color: var(--text-secondary)">前缀</label>
+                    <label class="block text-xs mb-1" style="color: var(--text-secondary)">${t('token.prefix')}</label>
                     <input type="text" class="input-field w-full rounded px-2 py-1 text-sm" placeholder="Bearer " 
-                           value="${escapeHtml(r.prefix || '')}" onchange="updateTokenRule(${i}, 'prefix', this.value)">
+                           value="${escapeHtml(r.prefix || '')}" data-onchange="updateTokenRule(${i}, 'prefix', this.value)">
                 </div>
             </div>
         </div>
@@ -1647,13 +2536,13 @@ function saveTokenRules() {
     tokenExtractRules = tokenExtractRules.filter(r => r.jsonPath || r.headerKey);
     const invalidKeys = tokenExtractRules.filter(r => r.headerKey && !isValidHeaderKey(r.headerKey));
     if (invalidKeys.length > 0) {
-        showToast('Header Key 只能包含英文字符', 'error');
+        showToast(t('globalHeaders.invalidKey'), 'error');
         return;
     }
     saveTokenExtractRulesToStorage();
     updateTokenRulesCount();
     closeTokenExtractModal();
-    showToast('Token 规则已保存');
+    showToast(t('token.saved'));
 }
 
 function clearTokenRules() {
@@ -1684,7 +2573,7 @@ function extractTokenFromResponse(responseData) {
                 }
                 saveGlobalHeadersToStorage();
                 updateHeadersCount();
-                showToast(`已提取 ${rule.headerKey}`);
+                showToast(t('token.extracted', { header: rule.headerKey }));
                 if (currentApi) renderGlobalHeaders();
             }
         } catch (e) {
@@ -1734,13 +2623,13 @@ function copyResponse() {
     const responseEl = document.getElementById('response-content');
     const text = responseEl.textContent || responseEl.innerText;
     
-    if (!text || text === '点击"发送请求"查看响应结果') {
-        showToast('暂无响应内容', 'error');
+    if (!text || text === t('response.placeholder')) {
+        showToast(t('response.empty'), 'error');
         return;
     }
     
     navigator.clipboard.writeText(text).then(() => {
-        showToast('已复制到剪贴板');
+        showToast(t('common.copied'));
     }).catch(() => {
         // 降级方案
         const textarea = document.createElement('textarea');
@@ -1751,14 +2640,14 @@ function copyResponse() {
         textarea.select();
         document.execCommand('copy');
         document.body.removeChild(textarea);
-        showToast('已复制到剪贴板');
+        showToast(t('common.copied'));
     });
 }
 
 // 复制 cURL 命令
 function copyCurl() {
     if (!currentApi) {
-        showToast('请先选择接口', 'error');
+        showToast(t('debug.selectApiFirst'), 'error');
         return;
     }
     
@@ -1780,6 +2669,13 @@ function copyCurl() {
         if (h.key && h.value) headers[h.key] = h.value;
     });
     
+    // 接口需要认证时附加已填写的凭据
+    const auth = buildAuthParams(currentApi.api);
+    Object.assign(headers, auth.headers);
+    Object.entries(auth.query).forEach(([key, value]) => queryParams.set(key, value));
+    const cookies = Object.entries(auth.cookies).map(([key, value]) => `${key}=${value}`).join('; ');
+    if (cookies) headers['Cookie'] = cookies;
+    
     // 收集参数
     document.querySelectorAll('#debug-params-container input[data-param], #debug-params-container select[data-param]').forEach(input => {
         const name = input.dataset.param;
@@ -1836,10 +2732,227 @@ function copyCurl() {
     }
     
     navigator.clipboard.writeText(curl).then(() => {
-        showToast('cURL 命令已复制');
+        showToast(t('curl.copied'));
     }).catch(() => {
-        showToast('复制失败', 'error');
+        showToast(t('common.copyFailed'), 'error');
+    });
+}
+
+// ==================== 粘贴 cURL ====================
+
+function openPasteCurlModal() {
+    document.getElementById('paste-curl-modal').classList.remove('hidden');
+    document.getElementById('paste-curl-input')?.focus();
+}
+
+function closePasteCurlModal() {
+    document.getElementById('paste-curl-modal')?.classList.add('hidden');
+}
+
+// 由后端解析 cURL 命令并匹配接口，保证各主题行为一致
+async function importCurl() {
+    const text = document.getElementById('paste-curl-input').value.trim();
+    if (!text) {
+        showToast(t('curl.empty'), 'error');
+        return;
+    }
+    
+    try {
+        // 命令中常带有认证信息，放在请求体中发送，避免出现在访问日志与代理日志中
+        const res = await fetch('./curl', { method: 'POST', headers: { 'Content-Type': 'text/plain' }, body: text });
+        if (res.status === 404 || res.status === 405) throw new Error(t('curl.postRequired'));
+        const result = await res.json();
+        if (!res.ok) throw new Error(result.error || `HTTP ${res.status}`);
+        
+        const { request, operation } = result;
+        if (!operation) {
+            showToast(t('curl.noMatch', { method: request.method, path: request.path }), 'error');
+            return;
+        }
+        
+        await originalSelectApi(operation.path, operation.method);
+        updateMobileTitle(currentApi.api.summary || operation.path);
+        fillDebugFromCurl(request, operation);
+        closePasteCurlModal();
+        document.getElementById('paste-curl-input').value = '';
+        showToast(t('curl.imported'));
+    } catch (e) {
+        showToast(t('curl.parseFailed', { message: e.message }), 'error');
+    }
+}
+
+// 将解析结果填充到调试面板
+function fillDebugFromCurl(request, operation) {
+    const headers = {};
+    (request.headers || []).forEach(h => { headers[h.key.toLowerCase()] = h.value; });
+    
+    const form = {};
+    (request.form || []).forEach(f => { if (!f.file) form[f.name] = f.value; });
+    // application/x-www-form-urlencoded 请求体按表单参数处理
+    if (!request.form?.length && request.body && !/^\s*[\[{]/.test(request.body)) {
+        new URLSearchParams(request.body).forEach((v, k) => { form[k] = v; });
+    }
+    
+    const usedHeaders = new Set();
+    document.querySelectorAll('#debug-params-container [data-param]').forEach(input => {
+        if (input.dataset.type === 'file') return;
+        const name = input.dataset.param;
+        let value;
+        switch (input.dataset.in) {
+            case 'path':
+                value = operation.pathParams?.[name];
+                break;
+            case 'query':
+                value = request.query?.[name]?.[0];
+                break;
+            case 'header':
+                value = headers[name.toLowerCase()];
+                usedHeaders.add(name.toLowerCase());
+                break;
+            case 'formData':
+                value = form[name];
+                break;
+        }
+        if (value === undefined) return;
+        input.value = value;
+        saveDebugParam(name, value);
+    });
+    
+    // 未在接口参数中声明的请求头（如 Authorization）合并到全局请求头
+    const skipHeaders = ['content-type', 'content-length', 'accept', 'accept-encoding', 'accept-language', 'user-agent', 'host', 'connection', 'origin', 'referer', 'cache-control', 'pragma'];
+    let headerChanged = false;
+    (request.headers || []).forEach(h => {
+        const key = h.key.toLowerCase();
+        if (usedHeaders.has(key) || skipHeaders.includes(key) || key.startsWith('sec-') || !isValidHeaderKey(h.key)) return;
+        const existing = globalHeaders.find(g => g.key.toLowerCase() === key);
+        if (existing) {
+            existing.value = h.value;
+        } else {
+            globalHeaders.push({ key: h.key, value: h.value });
+        }
+        headerChanged = true;
     });
+    if (headerChanged) {
+        saveGlobalHeadersToStorage();
+        updateHeadersCount();
+        renderGlobalHeaders();
+    }
+    
+    // 请求体
+    if (request.body && /^\s*[\[{]/.test(request.body) && !document.getElementById('debug-body-container').classList.contains('hidden')) {
+        let body = request.body;
+        try {
+            body = JSON.stringify(JSON.parse(body), null, 2);
+        } catch (e) {}
+        document.getElementById('debug-body').value = body;
+        saveDebugBody(body);
+        syncJsonToFields();
+        saveBodyFieldsData();
+    }
+}
+
+// ==================== 变更日志 ====================
+
+let changelogData = null;
+let changedOperations = {}; // "GET /path" -> new / changed
+
+// 加载变更日志，标记侧边栏中新增/变更的接口
+async function loadChangelog() {
+    if (!config.history) return;
+    document.getElementById('changelog-btn')?.classList.remove('hidden');
+    try {
+        const res = await fetch('./changelog');
+        if (!res.ok) return;
+        changelogData = await res.json();
+        changedOperations = changelogData.changelog?.operations || {};
+        if (swaggerData) renderApiList(document.getElementById('search-input')?.value || '');
+    } catch (e) {
+        console.log('Changelog not available');
+    }
+}
+
+// 接口的新增/变更标记
+function renderChangeBadge(path, method) {
+    const state = changedOperations[`${method.toUpperCase()} ${path}`];
+    if (state === 'new') {
+        return '<span class="px-1.5 py-0.5 rounded text-xs font-medium bg-green-500 text-white" title="' + t('changelog.new') + '">new</span>';
+    }
+    if (state === 'changed') {
+        return '<span class="px-1.5 py-0.5 rounded text-xs font-medium bg-yellow-500 text-white" title="' + t('changelog.changed') + '">changed</span>';
+    }
+    return '';
+}
+
+async function openChangelogModal() {
+    document.getElementById('changelog-modal').classList.remove('hidden');
+    if (!changelogData) await loadChangelog();
+    renderChangelog();
+}
+
+function closeChangelogModal() {
+    document.getElementById('changelog-modal')?.classList.add('hidden');
+}
+
+// 切换对比的版本
+async function changeChangelogVersions() {
+    const from = document.getElementById('changelog-from').value;
+    const to = document.getElementById('changelog-to').value;
+    try {
+        const res = await fetch(`./changelog?from=${encodeURIComponent(from)}&to=${encodeURIComponent(to)}`);
+        changelogData = await res.json();
+    } catch (e) {
+        showToast(t('changelog.loadFailed'), 'error');
+    }
+    renderChangelog();
+}
+
+function renderChangelog() {
+    const container = document.getElementById('changelog-content');
+    if (!container) return;
+    const versions = changelogData?.versions || [];
+    const changelog = changelogData?.changelog;
+    if (versions.length < 2) {
+        container.innerHTML = `<p class="text-center py-8 text-sm" style="color: var(--text-secondary)">${t('changelog.notEnough')}</p>`;
+        return;
+    }
+
+    const label = v => `${escapeHtml(v.version || '-')} · ${v.hash.slice(0, 7)} · ${new Date(v.time).toLocaleString()}`;
+    const options = selected => versions.slice().reverse().map(v =>
+        `<option value="${v.hash}" ${v.hash === selected ? 'selected' : ''}>${label(v)}</option>`).join('');
+    let html = `
+        <div class="flex items-center gap-2 mb-4 text-sm">
+            <select id="changelog-from" data-onchange="changeChangelogVersions()" class="input-field flex-1 rounded px-2 py-1.5 text-sm">${options(changelog?.from?.hash)}</select>
+            <i class="fas fa-arrow-right" style="color: var(--text-secondary)"></i>
+            <select id="changelog-to" data-onchange="changeChangelogVersions()" class="input-field flex-1 rounded px-2 py-1.5 text-sm">${options(changelog?.to?.hash)}</select>
+        </div>
+    `;
+    if (!changelog) {
+        html += `<p class="text-center py-8 text-sm text-red-500">${escapeHtml(changelogData?.error || t('changelog.compareFailed'))}</p>`;
+        container.innerHTML = html;
+        return;
+    }
+
+    const changes = changelog.diff.changes || [];
+    if (changes.length === 0) {
+        html += `<p class="text-center py-8 text-sm" style="color: var(--text-secondary)">${t('changelog.noChanges')}</p>`;
+        container.innerHTML = html;
+        return;
+    }
+    const section = (title, icon, list) => list.length === 0 ? '' : `
+        <div class="mb-4">
+            <div class="font-medium mb-2 flex items-center gap-2"><i class="fas ${icon}"></i>${title}（${list.length}）</div>
+            ${list.map(c => `
+                <div class="text-sm py-1.5 px-2 rounded mb-1" style="background: var(--bg-tertiary)">
+                    ${c.operation ? `<code class="font-mono text-xs mr-1">${escapeHtml(c.operation)}</code>` : ''}
+                    ${escapeHtml(c.message)}
+                    ${c.location ? `<span class="font-mono text-xs ml-1" style="color: var(--text-secondary)">${escapeHtml(c.location)}</span>` : ''}
+                </div>
+            `).join('')}
+        </div>
+    `;
+    html += section(t('changelog.breaking'), 'fa-exclamation-triangle text-red-500', changes.filter(c => c.breaking));
+    html += section(t('changelog.other'), 'fa-info-circle text-blue-500', changes.filter(c => !c.breaking));
+    container.innerHTML = html;
 }
 
 // ==================== 发送请求 ====================
@@ -1869,7 +2982,7 @@ async function sendRequest() {
     });
     
     if (missingParams.length > 0) {
-        showToast(`请填写必填参数: ${missingParams.join(', ')}`, 'error');
+        showToast(t('debug.missingParams', { params: missingParams.join(', ') }), 'error');
         return;
     }
     
@@ -1905,6 +3018,18 @@ async function sendRequest() {
         }
     });
     
+    // 接口需要认证时附加已填写的凭据（即将过期的 OAuth2 令牌先刷新）
+    await refreshExpiringTokens(api);
+    const auth = buildAuthParams(api);
+    Object.entries(auth.headers).forEach(([key, value]) => {
+        if (isValidHeaderKey(key)) headers[key] = encodeHeaderValue(value);
+    });
+    Object.entries(auth.query).forEach(([key, value]) => queryParams.set(key, value));
+    Object.entries(auth.cookies).forEach(([key, value]) => {
+        // 浏览器不允许手动设置 Cookie 请求头，写入文档页面的 Cookie（仅同源接口生效）
+        document.cookie = `${encodeURIComponent(key)}=${encodeURIComponent(value)}; path=/`;
+    });
+    
     document.querySelectorAll('#debug-params-container input[data-param], #debug-params-container select[data-param]').forEach(input => {
         const name = input.dataset.param;
         const location = input.dataset.in;
@@ -1950,7 +3075,7 @@ async function sendRequest() {
     setRequestLoading(true);
     
     const responseContent = document.getElementById('response-content');
-    responseContent.innerHTML = '<i class="fas fa-spinner fa-spin mr-2"></i>请求中...';
+    responseContent.innerHTML = '<i class="fas fa-spinner fa-spin mr-2"></i>' + t('debug.sending');
     
     const startTime = Date.now();
     try {
@@ -1997,26 +3122,26 @@ async function sendRequest() {
                     <div style="width: 48px; height: 48px; border-radius: 12px; display: flex; align-items: center; justify-content: center; margin-bottom: 12px; background: var(--primary);">
                         <i class="${fileIcon}" style="font-size: 20px; color: white;"></i>
                     </div>
-                    <p style="font-size: 14px; font-weight: 500; margin-bottom: 16px; color: var(--text-primary);">文件已准备就绪</p>
+                    <p style="font-size: 14px; font-weight: 500; margin-bottom: 16px; color: var(--text-primary);">${t('file.ready')}</p>
                     <div style="width: 100%; border-radius: 8px; padding: 12px; margin-bottom: 16px; background: var(--bg-tertiary); font-size: 13px;">
                         <div style="display: flex; justify-content: space-between; padding: 6px 0; border-bottom: 1px dashed var(--border);">
-                            <span style="color: var(--text-secondary);">文件名</span>
+                            <span style="color: var(--text-secondary);">${t('file.name')}</span>
                             <span style="font-weight: 500; max-width: 120px; overflow: hidden; text-overflow: ellipsis; white-space: nowrap;" title="${escapeHtml(filename)}">${escapeHtml(filename)}</span>
                         </div>
                         <div style="display: flex; justify-content: space-between; padding: 6px 0; border-bottom: 1px dashed var(--border);">
-                            <span style="color: var(--text-secondary);">大小</span>
+                            <span style="color: var(--text-secondary);">${t('file.size')}</span>
                             <span style="font-weight: 500;">${formatSize(size)}</span>
                         </div>
                         <div style="display: flex; justify-content: space-between; padding: 6px 0;">
-                            <span style="color: var(--text-secondary);">类型</span>
+                            <span style="color: var(--text-secondary);">${t('params.type')}</span>
                             <span style="font-weight: 500;">${escapeHtml(getFileTypeName(contentType))}</span>
                         </div>
                     </div>
                     <a href="${downloadUrl}" download="${escapeHtml(filename)}" 
                        style="display: inline-flex; align-items: center; gap: 6px; padding: 10px 20px; border-radius: 8px; color: white; font-weight: 500; font-size: 14px; text-decoration: none; background: var(--primary);"
-                       onclick="setTimeout(() => URL.revokeObjectURL('${downloadUrl}'), 100)">
+                       data-onclick="revokeObjectURLLater('${downloadUrl}')">
                         <i class="fas fa-download"></i>
-                        <span>下载文件</span>
+                        <span>${t('file.download')}</span>
                     </a>
                 </div>
             `;
@@ -2064,8 +3189,8 @@ function setRequestLoading(loading) {
     if (btn) {
         btn.disabled = loading;
         btn.innerHTML = loading 
-            ? '<i class="fas fa-spinner fa-spin mr-2"></i>请求中...'
-            : '<i class="fas fa-paper-plane mr-2"></i>发送请求';
+            ? '<i class="fas fa-spinner fa-spin mr-2"></i>' + t('debug.sending')
+            : '<i class="fas fa-paper-plane mr-2"></i>' + t('debug.send');
         btn.style.opacity = loading ? '0.7' : '1';
         btn.style.cursor = loading ? 'not-allowed' : 'pointer';
     }
@@ -2189,41 +3314,41 @@ function getFileTypeName(contentType) {
     const type = contentType.toLowerCase();
     
     const typeNames = {
-        'application/pdf': 'PDF 文档',
-        'application/msword': 'Word 文档',
-        'application/vnd.openxmlformats-officedocument.wordprocessingml.document': 'Word 文档',
-        'application/vnd.ms-excel': 'Excel 表格',
-        'application/vnd.openxmlformats-officedocument.spreadsheetml.sheet': 'Excel 表格',
-        'application/vnd.ms-powerpoint': 'PowerPoint 演示',
-        'application/vnd.openxmlformats-officedocument.presentationml.presentation': 'PowerPoint 演示',
-        'application/zip': 'ZIP 压缩包',
-        'application/x-zip-compressed': 'ZIP 压缩包',
-        'application/x-rar-compressed': 'RAR 压缩包',
-        'application/x-7z-compressed': '7Z 压缩包',
-        'application/gzip': 'GZIP 压缩包',
-        'application/octet-stream': '二进制文件',
-        'image/png': 'PNG 图片',
-        'image/jpeg': 'JPEG 图片',
-        'image/gif': 'GIF 图片',
-        'image/svg+xml': 'SVG 图片',
-        'image/webp': 'WebP 图片',
-        'audio/mpeg': 'MP3 音频',
-        'audio/wav': 'WAV 音频',
-        'video/mp4': 'MP4 视频',
-        'video/webm': 'WebM 视频',
-        'text/plain': '文本文件',
-        'text/csv': 'CSV 文件',
-        'application/json': 'JSON 文件',
-        'application/xml': 'XML 文件',
+        'application/pdf': t('file.pdf'),
+        'application/msword': t('file.word'),
+        'application/vnd.openxmlformats-officedocument.wordprocessingml.document': t('file.word'),
+        'application/vnd.ms-excel': t('file.excel'),
+        'application/vnd.openxmlformats-officedocument.spreadsheetml.sheet': t('file.excel'),
+        'application/vnd.ms-powerpoint': t('file.powerpoint'),
+        'application/vnd.openxmlformats-officedocument.presentationml.presentation': t('file.powerpoint'),
+        'application/zip': t('file.zip'),
+        'application/x-zip-compressed': t('file.zip'),
+        'application/x-rar-compressed': t('file.rar'),
+        'application/x-7z-compressed': t('file.7z'),
+        'application/gzip': t('file.gzip'),
+        'application/octet-stream': t('file.binary'),
+        'image/png': t('file.png'),
+        'image/jpeg': t('file.jpeg'),
+        'image/gif': t('file.gif'),
+        'image/svg+xml': t('file.svg'),
+        'image/webp': t('file.webp'),
+        'audio/mpeg': t('file.mp3'),
+        'audio/wav': t('file.wav'),
+        'video/mp4': t('file.mp4'),
+        'video/webm': t('file.webm'),
+        'text/plain': t('file.text'),
+        'text/csv': t('file.csv'),
+        'application/json': t('file.json'),
+        'application/xml': t('file.xml'),
     };
     
     if (typeNames[type]) return typeNames[type];
     
     // 通用匹配
-    if (type.startsWith('image/')) return '图片文件';
-    if (type.startsWith('audio/')) return '音频文件';
-    if (type.startsWith('video/')) return '视频文件';
-    if (type.startsWith('text/')) return '文本文件';
+    if (type.startsWith('image/')) return t('file.image');
+    if (type.startsWith('audio/')) return t('file.audio');
+    if (type.startsWith('video/')) return t('file.video');
+    if (type.startsWith('text/')) return t('file.text');
     
     return contentType;
 }
@@ -2261,17 +3386,17 @@ function toggleResponseExpand() {
     if (isResponseExpanded) {
         responseContent.style.maxHeight = '';
         responseContent.style.overflow = '';
-        expandBtn.innerHTML = '<i class="fas fa-chevron-up mr-1"></i>收起';
+        expandBtn.innerHTML = '<i class="fas fa-chevron-up mr-1"></i>' + t('response.collapse');
     } else {
         responseContent.style.maxHeight = '300px';
         responseContent.style.overflow = 'hidden';
-        expandBtn.innerHTML = '<i class="fas fa-chevron-down mr-1"></i>展开全部';
+        expandBtn.innerHTML = '<i class="fas fa-chevron-down mr-1"></i>' + t('response.expandAll');
     }
 }
 
 function toggleResponseFormat() {
     if (!lastResponseJson) {
-        showToast('无 JSON 响应可格式化', 'error');
+        showToast(t('response.noJson'), 'error');
         return;
     }
     
@@ -2282,11 +3407,11 @@ function toggleResponseFormat() {
     if (isResponseFormatted) {
         responseContent.innerHTML = syntaxHighlight(JSON.stringify(lastResponseJson, null, 2));
         formatBtn.innerHTML = '<i class="fas fa-compress-alt"></i>';
-        formatBtn.title = '压缩';
+        formatBtn.title = t('response.minify');
     } else {
         responseContent.innerHTML = syntaxHighlight(JSON.stringify(lastResponseJson));
         formatBtn.innerHTML = '<i class="fas fa-expand-alt"></i>';
-        formatBtn.title = '格式化';
+        formatBtn.title = t('response.format');
     }
 }
 
@@ -2295,7 +3420,7 @@ function toggleResponseHeaders() {
     const content = document.getElementById('response-headers-content');
     
     if (!lastResponseHeaders) {
-        showToast('暂无响应头', 'error');
+        showToast(t('response.noHeaders'), 'error');
         return;
     }
     
@@ -2385,10 +3510,38 @@ function loadUIThemeFromStorage() {
 }
 
 function openUIThemeModal() {
+    renderCustomUIThemes();
     document.getElementById('ui-theme-modal').classList.remove('hidden');
     updateUIThemeButtonStates();
 }
 
+// 服务端注册的自定义主题（config.themes 中内置主题以外的）追加到风格列表
+function renderCustomUIThemes() {
+    const template = document.querySelector('#ui-theme-modal .ui-theme-btn');
+    if (!template) return;
+    const list = template.parentElement;
+    for (const theme of config.themes || []) {
+        if ([...list.querySelectorAll('.ui-theme-btn')].some(btn => btn.dataset.uiTheme === theme)) continue;
+        const btn = document.createElement('button');
+        btn.className = template.className;
+        btn.style.cssText = template.style.cssText;
+        btn.dataset.uiTheme = theme;
+        btn.addEventListener('click', () => switchUITheme(theme));
+        btn.innerHTML = `
+            <div class="flex items-center gap-3">
+                <div class="w-12 h-12 rounded-lg flex items-center justify-center text-white" style="background: var(--primary)">
+                    <i class="fas fa-palette"></i>
+                </div>
+                <div>
+                    <div class="font-semibold">${escapeHtml(theme)}</div>
+                    <div class="text-sm" style="color: var(--text-secondary)">${t('uiTheme.custom')}</div>
+                </div>
+            </div>
+        `;
+        list.appendChild(btn);
+    }
+}
+
 function closeUIThemeModal() {
     document.getElementById('ui-theme-modal').classList.add('hidden');
 }
@@ -2414,17 +3567,32 @@ function switchUITheme(theme) {
     window.location.href = currentUrl.toString();
 }
 
-function exportDoc() {
+async function exportDoc() {
     if (!swaggerData) return;
     
-    const blob = new Blob([JSON.stringify(swaggerData, null, 2)], { type: 'application/json' });
+    // 界面使用展开引用（deref）的文档或索引渲染，导出时获取原始文档，保留 $ref 与递归结构
+    let data;
+    try {
+        const res = await fetch('./swagger.json');
+        if (!res.ok) throw new Error(res.status);
+        data = await res.json();
+    } catch (e) {
+        showToast(t('export.failed'), 'error');
+        return;
+    }
+    // 文档页面随文档导出，加载导出的文档时会还原页面
+    if (guidePages) {
+        const pages = guidePages.pages.map(({ slug, title, markdown }) => ({ slug, title, markdown }));
+        data = { ...data, 'x-qingfeng-pages': { title: guidePages.title, pages } };
+    }
+    const blob = new Blob([JSON.stringify(data, null, 2)], { type: 'application/json' });
     const url = URL.createObjectURL(blob);
     const a = document.createElement('a');
     a.href = url;
     a.download = 'swagger.json';
     a.click();
     URL.revokeObjectURL(url);
-    showToast('导出成功');
+    showToast(t('export.success'));
 }
 
 
@@ -2460,8 +3628,8 @@ function syncMobileThemeIcon() {
 
 // 重写 selectApi 以支持移动端
 const originalSelectApi = selectApi;
-selectApi = function(path, method) {
-    originalSelectApi(path, method);
+selectApi = async function(path, method) {
+    await originalSelectApi(path, method);
     // 移动端自动关闭侧边栏
     if (window.innerWidth <= 768) {
         toggleSidebar();
@@ -2471,6 +3639,18 @@ selectApi = function(path, method) {
     updateMobileTitle(api.summary || path);
 };
 
+// 重写 showPage 以支持移动端
+const originalShowPage = showPage;
+showPage = function(slug, anchor) {
+    originalShowPage(slug, anchor);
+    // 移动端从侧边栏打开页面时关闭侧边栏
+    if (window.innerWidth <= 768 && document.getElementById('sidebar')?.classList.contains('open')) {
+        toggleSidebar();
+    }
+    const page = guidePages?.pages.find(p => p.slug === slug);
+    if (page) updateMobileTitle(page.title);
+};
+
 // 重写 applyTheme 以同步移动端图标
 const originalApplyTheme = applyTheme;
 applyTheme = function() {
@@ -0,0 +1,327 @@
+package qingfeng
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxValidateBodySize 校验时读取请求体的最大字节数
+const maxValidateBodySize = 10 << 20
+
+// operationParameters 合并路径级与操作级参数（操作级同名参数覆盖路径级），并解析 $ref
+func operationParameters(spec map[string]interface{}, path string, op map[string]interface{}) []map[string]interface{} {
+	var list []interface{}
+	if paths, ok := spec["paths"].(map[string]interface{}); ok {
+		if item, ok := paths[path].(map[string]interface{}); ok {
+			if params, ok := item["parameters"].([]interface{}); ok {
+				list = append(list, params...)
+			}
+		}
+	}
+	if params, ok := op["parameters"].([]interface{}); ok {
+		list = append(list, params...)
+	}
+
+	var result []map[string]interface{}
+	index := make(map[string]int)
+	for _, p := range list {
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ref := getString(param, "$ref"); ref != "" {
+			if param = resolveRef(spec, ref); param == nil {
+				continue
+			}
+		}
+		key := getString(param, "in") + ":" + getString(param, "name")
+		if i, ok := index[key]; ok {
+			result[i] = param
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, param)
+	}
+	return result
+}
+
+// parameterSchema 返回参数的 schema；Swagger 2.0 风格参数（type 直接写在参数上）会被组装为 schema
+func parameterSchema(param map[string]interface{}) map[string]interface{} {
+	if schema, ok := param["schema"].(map[string]interface{}); ok {
+		return schema
+	}
+	schema := make(map[string]interface{})
+	for _, k := range []string{"type", "format", "items", "enum", "default", "minimum", "maximum", "minLength", "maxLength", "pattern"} {
+		if v, ok := param[k]; ok {
+			schema[k] = v
+		}
+	}
+	return schema
+}
+
+// isFileSchema 判断参数或字段是否为文件上传
+func isFileSchema(schema map[string]interface{}) bool {
+	return getString(schema, "type") == "file" || getString(schema, "format") == "binary"
+}
+
+// operationRequestBody 返回操作的 requestBody（解析 $ref）
+func operationRequestBody(spec, op map[string]interface{}) map[string]interface{} {
+	body, _ := op["requestBody"].(map[string]interface{})
+	if ref := getString(body, "$ref"); ref != "" {
+		body = resolveRef(spec, ref)
+	}
+	return body
+}
+
+// pickMediaType 从 content 中选择与 contentType 匹配的媒体类型，
+// contentType 为空时优先选择 JSON
+func pickMediaType(content map[string]interface{}, contentType string) (string, map[string]interface{}) {
+	if len(content) == 0 {
+		return "", nil
+	}
+	if contentType != "" {
+		if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+			contentType = mt
+		}
+		if media, ok := content[contentType].(map[string]interface{}); ok {
+			return contentType, media
+		}
+		// 通配类型，如 application/*、*/*
+		for key, v := range content {
+			media, _ := v.(map[string]interface{})
+			if key == "*/*" || (strings.HasSuffix(key, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(key, "*"))) {
+				return contentType, media
+			}
+		}
+		return "", nil
+	}
+	for _, key := range []string{"application/json", "*/*"} {
+		if media, ok := content[key].(map[string]interface{}); ok {
+			return key, media
+		}
+	}
+	keys := contentKeys(content)
+	for _, key := range keys {
+		if isJSONMediaType(key) {
+			media, _ := content[key].(map[string]interface{})
+			return key, media
+		}
+	}
+	media, _ := content[keys[0]].(map[string]interface{})
+	return keys[0], media
+}
+
+// isJSONMediaType 判断是否为 JSON 媒体类型（application/json 或 +json 后缀）
+func isJSONMediaType(mediaType string) bool {
+	if mt, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = mt
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// validateRequest 按匹配到的操作校验请求的路径、查询、请求头、Cookie 参数与请求体
+// 读取的请求体会被还原，后续处理器仍可读取完整的请求体；超过 maxValidateBodySize 的请求体不做校验
+func validateRequest(spec map[string]interface{}, match *operationMatch, r *http.Request) []ValidationError {
+	var errs []ValidationError
+
+	var body []byte
+	oversize := false
+	if r.Body != nil && r.Body != http.NoBody {
+		body, _ = io.ReadAll(io.LimitReader(r.Body, maxValidateBodySize+1))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		if int64(len(body)) > maxValidateBodySize {
+			body, oversize = nil, true
+		}
+	}
+
+	// 表单在副本上解析，避免消耗原始请求体
+	var form *http.Request
+	parseForm := func() *http.Request {
+		if form == nil {
+			form = r.Clone(r.Context())
+			form.Body = io.NopCloser(bytes.NewReader(body))
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+				form.ParseMultipartForm(maxValidateBodySize)
+			} else {
+				form.ParseForm()
+			}
+		}
+		return form
+	}
+
+	for _, param := range operationParameters(spec, match.Path, match.op) {
+		name, in := getString(param, "name"), getString(param, "in")
+		required, _ := param["required"].(bool)
+		schema := parameterSchema(param)
+
+		var values []string
+		switch in {
+		case "path":
+			if v, ok := match.PathParams[name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = r.URL.Query()[name]
+		case "header":
+			values = r.Header.Values(name)
+		case "cookie":
+			if c, err := r.Cookie(name); err == nil {
+				values = []string{c.Value}
+			}
+		case "formData":
+			if oversize {
+				continue
+			}
+			f := parseForm()
+			if isFileSchema(schema) {
+				if f.MultipartForm != nil && len(f.MultipartForm.File[name]) > 0 {
+					values = []string{""}
+				}
+				if required && len(values) == 0 {
+					errs = append(errs, ValidationError{In: in, Field: name, Type: "file", Rule: "required", Message: "缺少必填文件"})
+				}
+				continue
+			}
+			values = f.PostForm[name]
+		default:
+			continue
+		}
+
+		if len(values) == 0 {
+			if required || in == "path" {
+				errs = append(errs, ValidationError{In: in, Field: name, Type: schemaTypeName(schema), Rule: "required", Message: "缺少必填参数"})
+			}
+			continue
+		}
+		errs = append(errs, validateValue(spec, schema, coerceParam(spec, schema, values), in, name)...)
+	}
+
+	requestBody := operationRequestBody(spec, match.op)
+	if requestBody == nil {
+		return errs
+	}
+	if oversize {
+		return errs
+	}
+	required, _ := requestBody["required"].(bool)
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required {
+			errs = append(errs, ValidationError{In: "body", Rule: "required", Message: "缺少请求体"})
+		}
+		return errs
+	}
+
+	content, _ := requestBody["content"].(map[string]interface{})
+	contentType := r.Header.Get("Content-Type")
+	mediaType, media := pickMediaType(content, contentType)
+	if media == nil {
+		if contentType == "" {
+			contentType = "(空)"
+		}
+		return append(errs, ValidationError{In: "body", Rule: "contentType", Expected: contentKeys(content), Message: "不支持的 Content-Type: " + contentType})
+	}
+	schema, _ := media["schema"].(map[string]interface{})
+	if schema == nil {
+		return errs
+	}
+
+	switch {
+	case isJSONMediaType(mediaType) || mediaType == "*/*" && isJSONMediaType(contentType):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return append(errs, ValidationError{In: "body", Type: schemaTypeName(schema), Rule: "json", Message: "请求体不是合法的 JSON: " + err.Error()})
+		}
+		errs = append(errs, validateValue(spec, schema, value, "body", "")...)
+	case strings.HasPrefix(mediaType, "multipart/") || mediaType == "application/x-www-form-urlencoded":
+		errs = append(errs, validateFormBody(spec, schema, parseForm())...)
+	}
+	return errs
+}
+
+// validateFormBody 按对象 schema 校验表单请求体，字段值按属性类型转换
+func validateFormBody(spec, schema map[string]interface{}, form *http.Request) []ValidationError {
+	schema = derefSchema(spec, schema)
+	props, _ := schema["properties"].(map[string]interface{})
+	obj := make(map[string]interface{})
+	for key, values := range form.PostForm {
+		prop, _ := props[key].(map[string]interface{})
+		obj[key] = coerceParam(spec, prop, values)
+	}
+	if form.MultipartForm != nil {
+		for key := range form.MultipartForm.File {
+			obj[key] = ""
+		}
+	}
+	// 文件字段只校验是否存在
+	fileless := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		fileless[k] = v
+	}
+	filtered := make(map[string]interface{}, len(props))
+	for key, p := range props {
+		prop, _ := p.(map[string]interface{})
+		if isFileSchema(derefSchema(spec, prop)) {
+			filtered[key] = map[string]interface{}{}
+			continue
+		}
+		filtered[key] = p
+	}
+	fileless["properties"] = filtered
+	return validateValue(spec, fileless, obj, "formData", "")
+}
+
+// contentKeys 返回 content 中声明的媒体类型列表
+func contentKeys(content map[string]interface{}) []string {
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateResponse 按文档中对应状态码的响应定义校验响应体
+// 状态码未在文档中声明（且没有 default）时返回 status 规则的错误
+func validateResponse(spec map[string]interface{}, match *operationMatch, status int, contentType string, body []byte) []ValidationError {
+	code := strconv.Itoa(status)
+	_, response := selectResponse(spec, match.op, code)
+	if response == nil {
+		return []ValidationError{{In: "response", Field: "status", Rule: "status", Expected: documentedStatuses(match.op), Message: "响应状态码 " + code + " 未在文档中声明"}}
+	}
+
+	content, _ := response["content"].(map[string]interface{})
+	if len(content) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	mediaType, media := pickMediaType(content, contentType)
+	if media == nil {
+		if contentType == "" {
+			contentType = "(空)"
+		}
+		return []ValidationError{{In: "response", Rule: "contentType", Expected: contentKeys(content), Message: "响应 Content-Type 未在文档中声明: " + contentType}}
+	}
+	schema, _ := media["schema"].(map[string]interface{})
+	if schema == nil || !(isJSONMediaType(mediaType) || mediaType == "*/*" && isJSONMediaType(contentType)) {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []ValidationError{{In: "response", Type: schemaTypeName(schema), Rule: "json", Message: "响应体不是合法的 JSON: " + err.Error()}}
+	}
+	return validateValue(spec, schema, value, "response", "")
+}
+
+// documentedStatuses 返回操作中声明的响应状态码
+func documentedStatuses(op map[string]interface{}) []string {
+	responses, _ := op["responses"].(map[string]interface{})
+	return contentKeys(responses)
+}